@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"time"
@@ -9,14 +10,16 @@ import (
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/bot"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/logging"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/metrics"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
 
 	"github.com/go-redis/redis"
 	"github.com/go-sql-driver/mysql"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/jmoiron/sqlx"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 func main() {
@@ -24,23 +27,54 @@ func main() {
 	appContext, appDone := context.WithCancel(context.Background())
 	defer appDone()
 
+	// config file, env vars & flags
+	configOpts := config.Flags(flag.CommandLine)
+	flag.Parse()
+
+	if config.IsCheckCommand(flag.Args()) {
+		os.Exit(config.Check(os.Stdout, *configOpts))
+	}
+
+	cfg, err := config.Load(*configOpts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.Grpc.Mode != "production" {
+		debug.DebugStruct(cfg)
+	}
+
+	// running config, reloaded on file change & SIGHUP
+	store := config.NewStore(cfg, *configOpts)
+	store.OnReload(logging.Reload)
+
 	// init sub services
-	cfg := config.LoadConfig()
 	initLogger(&cfg)
-	ds := datasource.NewDataSource(&cfg, initDB(&cfg), initRedis(&cfg))
+	shutdownTracing := initTracing(&cfg)
+	defer shutdownTracing()
+	ds := datasource.NewDataSource(store, initDB(&cfg), initRedis(&cfg))
+	ds.WatchCacheMetrics()
 
-	// init bot service
-	botApi, err := tgbotapi.NewBotAPI(os.Getenv(cfg.Telegram.TokenEnv))
+	// init bot service, every Bot API call is recorded in metrics
+	botApi, err := tgbotapi.NewBotAPIWithClient(cfg.Telegram.Token.Value.Reveal(), tgbotapi.APIEndpoint, metrics.NewTelegramClient())
 	if err != nil {
 		panic(err)
 	}
-	botServer := bot.NewTelegramBotService(botApi, ds)
+	botServer := bot.NewTelegramBotService(appContext, botApi, ds)
+	go botServer.PurgeMessageHistory(appContext)
+
+	if err := store.Watch(appContext, configOpts.Path, cfg.I18n.Dir, cfg.I18n.TemplateDir); err != nil {
+		panic(err)
+	}
 
 	// setup update channel for polling
 	updateConfig := tgbotapi.NewUpdate(0)
 	updateChan := botServer.BotAPI.GetUpdatesChan(updateConfig)
 	defer botServer.BotAPI.StopReceivingUpdates()
 
+	metrics.WatchUpdateQueue(func() int { return len(updateChan) })
+	metrics.Serve(cfg.Metrics.BotListener)
+
 	// channels for propagating datas
 	quit := make(chan struct{})
 	fatalError := make(chan error, 1) // make it unbuffered so it won't block
@@ -88,17 +122,26 @@ func main() {
 }
 
 func initLogger(cfg *config.AppConfig) {
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	zerolog.TimeFieldFormat = time.RFC3339
-	log.Logger = zerolog.New(&lumberjack.Logger{
-		Filename:   cfg.Telegram.Bot.Logfile,
-		MaxSize:    100,
-		MaxBackups: 3,
-		MaxAge:     30,
-		Compress:   true,
-	})
-	log.Logger = log.With().Caller().Logger()
-	log.Logger = log.With().Timestamp().Logger()
+	if err := logging.Init(*cfg, cfg.Telegram.Bot.Logfile); err != nil {
+		panic(err)
+	}
+}
+
+// returns function flushing pending spans
+func initTracing(cfg *config.AppConfig) func() {
+	shutdown, err := tracing.Init(*cfg, "lord-bidoof-bot")
+	if err != nil {
+		panic(err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("tracing.shutdown")
+		}
+	}
 }
 
 func initRedis(cfg *config.AppConfig) *redis.Client {
@@ -123,6 +166,9 @@ func initDB(cfg *config.AppConfig) *sqlx.DB {
 		DBName:               cfg.DB.Database,
 		AllowNativePasswords: true,
 		CheckConnLiveness:    true,
+		ParseTime:            true,
+		Loc:                  time.UTC,
+		Params:               map[string]string{"time_zone": "'+00:00'"},
 	}
 
 	d := sqlx.MustConnect("mysql", dbConfig.FormatDSN())
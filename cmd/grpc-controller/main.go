@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"net"
 	"os"
@@ -10,24 +12,31 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/audit"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/logging"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/metrics"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/services"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
 
 	"github.com/go-redis/redis"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type App struct {
 	Config     *config.AppConfig
+	Store      *config.Store
 	DB         *sqlx.DB
 	Redis      *redis.Client
 	GrpcServer *grpc.Server
+
+	// flushes pending spans
+	ShutdownTracing func(context.Context) error
 }
 
 func main() {
@@ -42,13 +51,33 @@ func main() {
 func InitApp() *App {
 	app := new(App)
 
-	// INIT: config file
-	cfg := config.LoadConfig()
+	// INIT: config file, env vars & flags
+	configOpts := config.Flags(flag.CommandLine)
+	flag.Parse()
+
+	if config.IsCheckCommand(flag.Args()) {
+		os.Exit(config.Check(os.Stdout, *configOpts))
+	}
+
+	cfg, err := config.Load(*configOpts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.Grpc.Mode != "production" {
+		debug.DebugStruct(cfg)
+	}
 	app.Config = &cfg
 
 	// INIT: logger
 	app.InitLogger()
 
+	// INIT: config reload on file change & SIGHUP
+	app.InitReload(*configOpts)
+
+	// INIT: tracing
+	app.InitTracing()
+
 	// INIT: db
 	app.InitDB()
 
@@ -58,6 +87,9 @@ func InitApp() *App {
 	// INIT: gRPC server
 	app.InitGrpc()
 
+	// INIT: metrics endpoint
+	metrics.Serve(app.Config.Metrics.GrpcListener)
+
 	return app
 }
 
@@ -67,7 +99,7 @@ func (app *App) Run() error {
 	// channel for propagating handling error & OS interrupt
 	errChan := make(chan error)
 	fatalError := make(chan error, 1)
-	sigChan := make(chan os.Signal)
+	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
 
 	lst, err := net.Listen("tcp", app.Config.Grpc.Listener)
@@ -106,17 +138,30 @@ func (app *App) Run() error {
 }
 
 func (app *App) InitLogger() {
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	zerolog.TimeFieldFormat = time.RFC3339
-	log.Logger = zerolog.New(&lumberjack.Logger{
-		Filename:   app.Config.Grpc.Logfile,
-		MaxSize:    100,
-		MaxBackups: 3,
-		MaxAge:     30,
-		Compress:   true,
-	})
-	log.Logger = log.With().Caller().Logger()
-	log.Logger = log.With().Timestamp().Logger()
+	if err := logging.Init(*app.Config, app.Config.Grpc.Logfile); err != nil {
+		panic(err)
+	}
+}
+
+func (app *App) InitReload(opts config.Options) {
+	app.Store = config.NewStore(*app.Config, opts)
+	app.Store.OnReload(logging.Reload)
+
+	// bot only, the bot process reloads them itself
+	app.Store.Ignore("i18n.", "telegram.allowlist_only")
+
+	if err := app.Store.Watch(context.Background(), opts.Path); err != nil {
+		panic(err)
+	}
+}
+
+func (app *App) InitTracing() {
+	shutdown, err := tracing.Init(*app.Config, "lord-bidoof-grpc-controller")
+	if err != nil {
+		panic(err)
+	}
+
+	app.ShutdownTracing = shutdown
 }
 
 func (app *App) InitDB() {
@@ -128,6 +173,9 @@ func (app *App) InitDB() {
 		DBName:               app.Config.DB.Database,
 		AllowNativePasswords: true,
 		CheckConnLiveness:    true,
+		ParseTime:            true,
+		Loc:                  time.UTC,
+		Params:               map[string]string{"time_zone": "'+00:00'"},
 	}
 
 	app.DB = sqlx.MustConnect("mysql", dsn.FormatDSN())
@@ -148,15 +196,17 @@ func (app *App) InitRedis() {
 }
 
 func (app *App) InitGrpc() {
-	app.GrpcServer = grpc.NewServer()
-	ds := datasource.NewDataSource(app.Config, app.DB, app.Redis)
+	ds := datasource.NewDataSource(app.Store, app.DB, app.Redis)
+	ds.WatchCacheMetrics()
 
-	// get bot token from environment
-	token := os.Getenv(app.Config.Telegram.TokenEnv)
-	if len(token) == 0 {
-		panic("Empty bot token in environment variable")
-	}
-	bot, err := tgbotapi.NewBotAPI(token)
+	// mutating RPCs are recorded in the audit trail
+	app.GrpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(), tracing.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), audit.UnaryServerInterceptor(ds)),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(), tracing.StreamServerInterceptor(), metrics.StreamServerInterceptor(), audit.StreamServerInterceptor(ds)),
+	)
+
+	// token is read by config.Load from its secret source
+	bot, err := tgbotapi.NewBotAPIWithClient(app.Config.Telegram.Token.Value.Reveal(), tgbotapi.APIEndpoint, metrics.NewTelegramClient())
 	if err != nil {
 		panic(err)
 	}
@@ -166,6 +216,13 @@ func (app *App) InitGrpc() {
 }
 
 func (app *App) Cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := app.ShutdownTracing(ctx); err != nil {
+		fmt.Println(err)
+	}
+
 	if err := app.DB.Close(); err != nil {
 		fmt.Println(err)
 	}
@@ -0,0 +1,9 @@
+-- richer user profile for telegram_private_chat, all timestamps are in UTC
+ALTER TABLE telegram_private_chat
+    ADD COLUMN language_code VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN is_blocked    BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN created_at    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    ADD COLUMN last_seen_at  DATETIME    NULL,
+    ADD COLUMN started_at    DATETIME    NULL,
+    ADD COLUMN stopped_at    DATETIME    NULL;
@@ -0,0 +1,14 @@
+-- soft-delete for /stop, stopped users keep their profile until they /start again
+ALTER TABLE telegram_private_chat
+    ADD COLUMN deleted_at DATETIME NULL;
+
+-- append-only subscription history, for churn queries
+CREATE TABLE telegram_subscription_event (
+    id         BIGINT      NOT NULL AUTO_INCREMENT,
+    chat_id    BIGINT      NOT NULL,
+    event      VARCHAR(32) NOT NULL,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_subscription_event_chat (chat_id, created_at),
+    INDEX idx_subscription_event_time (created_at, event)
+);
@@ -0,0 +1,16 @@
+-- append-only history of inbound updates & outbound sends
+CREATE TABLE telegram_message (
+    id         BIGINT      NOT NULL AUTO_INCREMENT,
+    chat_id    BIGINT      NOT NULL,
+    direction  VARCHAR(8)  NOT NULL,
+    message_id BIGINT      NOT NULL,
+    command    VARCHAR(64) NOT NULL DEFAULT '',
+    text       TEXT        NOT NULL,
+    sent_at    DATETIME    NOT NULL,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_message_chat (chat_id, sent_at),
+    INDEX idx_message_sent (sent_at),
+    -- retention purge
+    INDEX idx_message_time (created_at)
+);
@@ -0,0 +1,4 @@
+-- language chosen by the user with /language, empty means follow the
+-- language reported by Telegram (language_code)
+ALTER TABLE telegram_private_chat
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT '';
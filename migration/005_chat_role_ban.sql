@@ -0,0 +1,19 @@
+-- roles granted in chat with /grant, admins listed in setting.yaml are not
+-- stored here
+CREATE TABLE telegram_chat_role (
+    chat_id    BIGINT      NOT NULL,
+    role       VARCHAR(32) NOT NULL,
+    granted_by BIGINT      NOT NULL,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, role)
+);
+
+-- updates of banned chats are dropped before any command runs, so /start
+-- can't undo a ban
+CREATE TABLE telegram_chat_ban (
+    chat_id    BIGINT       NOT NULL,
+    reason     VARCHAR(255) NOT NULL DEFAULT '',
+    banned_by  BIGINT       NOT NULL,
+    created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id)
+);
@@ -0,0 +1,8 @@
+-- chats allowed to use the bot when telegram.allowlist_only is on, admins &
+-- moderators are always allowed
+CREATE TABLE telegram_chat_allow (
+    chat_id    BIGINT   NOT NULL,
+    allowed_by BIGINT   NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id)
+);
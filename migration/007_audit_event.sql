@@ -0,0 +1,16 @@
+-- append-only trail of mutating gRPC controller calls
+CREATE TABLE telegram_audit_event (
+    id         BIGINT       NOT NULL AUTO_INCREMENT,
+    request_id VARCHAR(64)  NOT NULL DEFAULT '',
+    caller     VARCHAR(255) NOT NULL DEFAULT '',
+    peer       VARCHAR(255) NOT NULL DEFAULT '',
+    method     VARCHAR(255) NOT NULL,
+    params     TEXT         NOT NULL,
+    code       VARCHAR(32)  NOT NULL,
+    error      TEXT         NOT NULL,
+    created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_audit_event_method (method, created_at),
+    INDEX idx_audit_event_caller (caller, created_at),
+    INDEX idx_audit_event_time (created_at)
+);
@@ -0,0 +1,62 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// panic recovered from the function given to Run
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Run `fn` in a goroutine and wait for its result until `ctx` is done or
+// `timeout` elapses, whichever comes first (no timeout if it's 0). The context
+// given to `fn` is canceled when Run returns, so it can stop early.
+//
+// A panic in `fn` is recovered and returned as *PanicError. On timeout or
+// cancellation ctx.Err() is returned, while the goroutine is left to finish on
+// its own without ever blocking since its result is buffered.
+func Run[T any](ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		var res result
+		defer func() {
+			if v := recover(); v != nil {
+				res = result{err: &PanicError{v, debug.Stack()}}
+			}
+			done <- res
+		}()
+
+		res.val, res.err = fn(ctx)
+	}()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+
+	case res := <-done:
+		return res.val, res.err
+	}
+}
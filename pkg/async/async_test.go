@@ -0,0 +1,66 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	res, err := Run(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 42, res)
+
+	_, err = Run(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		return 0, errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+}
+
+func TestRunPanic(t *testing.T) {
+	_, err := Run(context.Background(), time.Second, func(ctx context.Context) (struct{}, error) {
+		panic("boom")
+	})
+
+	var panicErr *PanicError
+	if assert.ErrorAs(t, err, &panicErr) {
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	finished := make(chan struct{})
+	_, err := Run(context.Background(), 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		defer close(finished)
+		time.Sleep(50 * time.Millisecond)
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the goroutine must exit once `fn` returns, even though nobody waits
+	<-finished
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Run(ctx, 0, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
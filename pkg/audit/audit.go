@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/logging"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// metadata naming the caller, e.g. the calling service or operator. Calls
+// aren't authenticated, so it is recorded as claimed next to the peer address.
+const CALLER_METADATA = "x-caller"
+
+// size of the caller column, longer values are truncated
+const MAX_CALLER_LENGTH = 255
+
+// the audit write outlives the call, a client going away mustn't lose it
+const WRITE_TIMEOUT = 5 * time.Second
+
+// RPCs changing state, read-only ones are not audited
+var MUTATING = fullMethods(
+	"SendMessage",
+	"SendPhoto",
+	"SendDocument",
+	"SendSticker",
+	"SendMediaGroup",
+	"EditMessageText",
+	"DeleteMessage",
+	"PinMessage",
+	"UnpinMessage",
+	"ForwardMessage",
+	"ReloadControllerConfig",
+	"BanUser",
+	"UnbanUser",
+)
+
+// request fields (proto names) replaced by their length: message text,
+// captions & uploaded file data
+var REDACTED_FIELDS = map[string]bool{
+	"text": true,
+	"data": true,
+}
+
+// where audit events are written, implemented by *datasource.DataSource
+type Recorder interface {
+	InsertAuditEvent(context.Context, *datasource.AuditEvent) error
+}
+
+// Record every mutating RPC with its caller, params & result. Must come
+// after the logging interceptor, events carry its request ID.
+func UnaryServerInterceptor(r Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !MUTATING[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+		record(ctx, r, info.FullMethod, req, err)
+
+		return resp, err
+	}
+}
+
+// Streams are recorded with their first message as params, the header of
+// media uploads. File chunks following it are left out.
+func StreamServerInterceptor(r Recorder) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !MUTATING[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		s := &auditedStream{ServerStream: ss}
+		err := handler(srv, s)
+		record(ss.Context(), r, info.FullMethod, s.first, err)
+
+		return err
+	}
+}
+
+type auditedStream struct {
+	grpc.ServerStream
+	first any
+}
+
+func (s *auditedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+
+	return err
+}
+
+func record(ctx context.Context, r Recorder, method string, req any, err error) {
+	event := &datasource.AuditEvent{
+		RequestID: logging.RequestID(ctx),
+		Caller:    caller(ctx),
+		Method:    method,
+		Params:    params(req),
+		Code:      status.Code(err).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		event.Peer = p.Addr.String()
+	}
+	if err != nil {
+		event.Error = status.Convert(err).Message()
+	}
+
+	// keeps the call's logger & trace
+	wctx := log.Ctx(ctx).WithContext(trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx)))
+	wctx, cancel := context.WithTimeout(wctx, WRITE_TIMEOUT)
+	defer cancel()
+
+	if err := r.InsertAuditEvent(wctx, event); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("audit.insert")
+	}
+}
+
+func caller(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(CALLER_METADATA); len(v) != 0 {
+		if r := []rune(v[0]); len(r) > MAX_CALLER_LENGTH {
+			return string(r[:MAX_CALLER_LENGTH])
+		}
+		return v[0]
+	}
+
+	return ""
+}
+
+// request as JSON with REDACTED_FIELDS masked
+func params(req any) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "{}"
+	}
+
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return "{}"
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "{}"
+	}
+
+	b, err = json.Marshal(redact(v))
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if s, ok := item.(string); ok && REDACTED_FIELDS[k] {
+				v[k] = debug.MaskText(s)
+				continue
+			}
+			v[k] = redact(item)
+		}
+
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+
+	return v
+}
+
+func fullMethods(names ...string) map[string]bool {
+	methods := make(map[string]bool, len(names))
+	for _, name := range names {
+		methods["/"+telegrampb.TelegramService_ServiceDesc.ServiceName+"/"+name] = true
+	}
+
+	return methods
+}
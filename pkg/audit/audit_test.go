@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/logging"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type testRecorder struct {
+	events []*datasource.AuditEvent
+}
+
+func (r *testRecorder) InsertAuditEvent(_ context.Context, event *datasource.AuditEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func testContext() context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CALLER_METADATA, "ops-dashboard"))
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	r := new(testRecorder)
+	interceptor := UnaryServerInterceptor(r)
+
+	req := &telegrampb.SendMessageRequest{ChatId: 42, Text: "hello bidoof"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad entities")
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/SendMessage"}
+	_, err := interceptor(testContext(), req, info, handler)
+	assert.Error(t, err)
+
+	// read-only RPC is not recorded
+	info = &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/BotStatus"}
+	_, err = interceptor(testContext(), &telegrampb.BotStatusRequest{}, info, handler)
+	assert.Error(t, err)
+
+	require.Len(t, r.events, 1)
+	event := r.events[0]
+	assert.Equal(t, "/telegram.v1.TelegramService/SendMessage", event.Method)
+	assert.Equal(t, "ops-dashboard", event.Caller)
+	assert.Equal(t, "10.0.0.1:5000", event.Peer)
+	assert.Equal(t, "InvalidArgument", event.Code)
+	assert.Equal(t, "bad entities", event.Error)
+
+	var params map[string]any
+	require.NoError(t, json.Unmarshal([]byte(event.Params), &params))
+	assert.Equal(t, "42", params["chat_id"])
+	assert.Equal(t, "[12 chars]", params["text"])
+}
+
+// receives `msgs` in order
+type testStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []*telegrampb.SendPhotoRequest
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testStream) RecvMsg(m any) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+
+	proto.Merge(m.(*telegrampb.SendPhotoRequest), s.msgs[0])
+	s.msgs = s.msgs[1:]
+
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	r := new(testRecorder)
+	ss := &testStream{ctx: testContext(), msgs: []*telegrampb.SendPhotoRequest{
+		{Payload: &telegrampb.SendPhotoRequest_Header{Header: &telegrampb.PhotoHeader{
+			ChatId:  42,
+			Caption: &telegrampb.Caption{Text: "look"},
+		}}},
+		{Payload: &telegrampb.SendPhotoRequest_Chunk{Chunk: &telegrampb.FileChunk{Data: []byte("jpeg")}}},
+	}}
+
+	handler := func(srv any, ss grpc.ServerStream) error {
+		for {
+			if err := ss.RecvMsg(new(telegrampb.SendPhotoRequest)); err == io.EOF {
+				return nil
+			}
+		}
+	}
+
+	info := &grpc.StreamServerInfo{FullMethod: "/telegram.v1.TelegramService/SendPhoto"}
+	require.NoError(t, StreamServerInterceptor(r)(nil, ss, info, handler))
+
+	require.Len(t, r.events, 1)
+	assert.Equal(t, "OK", r.events[0].Code)
+	assert.JSONEq(t, `{"header": {"chat_id": "42", "caption": {"text": "[4 chars]"}}}`, r.events[0].Params)
+}
+
+// metadata longer than the audit columns must not lose the event
+func TestOversizedMetadata(t *testing.T) {
+	r := new(testRecorder)
+	audited := UnaryServerInterceptor(r)
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		logging.REQUEST_ID_METADATA, strings.Repeat("a", 100),
+		CALLER_METADATA, strings.Repeat("ö", 300),
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/SendMessage"}
+	_, err := logging.UnaryServerInterceptor()(ctx, &telegrampb.SendMessageRequest{ChatId: 42}, info, func(ctx context.Context, req any) (any, error) {
+		return audited(ctx, req, info, handler)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, r.events, 1)
+	assert.Len(t, r.events[0].RequestID, 32, "replaced by a generated ID")
+	assert.Equal(t, strings.Repeat("ö", MAX_CALLER_LENGTH), r.events[0].Caller)
+}
@@ -0,0 +1,439 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/i18n"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
+)
+
+const (
+	// entries per /users & /bans page
+	LIST_PAGE_SIZE = 20
+
+	// Telegram allows about 30 messages per second to different users
+	BROADCAST_INTERVAL = 50 * time.Millisecond
+
+	// period covered by /stats subscription counts
+	STATS_PERIOD = 7 * 24 * time.Hour
+)
+
+// Wrap `cmd` so it only runs for chats having `role`, others are told they
+// can't use it
+func (tg *TelegramBotService) requireRole(role string, cmd Command) Command {
+	return func(ctx context.Context, msg *tgbotapi.Message, args []string) {
+		if !tg.hasRole(ctx, msg.Chat.ID, role) {
+			log.Ctx(ctx).Warn().Str("role", role).Msg("command.forbidden")
+			tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "error.forbidden", nil), "requireRole")
+			return
+		}
+
+		cmd(ctx, msg, args)
+	}
+}
+
+func (tg *TelegramBotService) hasRole(ctx context.Context, chatId int64, role string) bool {
+	has, err := tg.HasChatRole(ctx, chatId, role)
+	if err != nil {
+		panic(err)
+	}
+
+	return has
+}
+
+// 2 for admins, 1 for moderators, 0 without role
+func (tg *TelegramBotService) roleRank(ctx context.Context, chatId int64) int {
+	switch {
+	case tg.hasRole(ctx, chatId, datasource.ROLE_ADMIN):
+		return 2
+	case tg.hasRole(ctx, chatId, datasource.ROLE_MODERATOR):
+		return 1
+	}
+
+	return 0
+}
+
+// Show user & subscription counts
+func (tg *TelegramBotService) StatsCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	active, err := tg.CountActivePrivateChat(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	events, err := tg.CountSubscriptionEvents(ctx, datasource.SubscriptionEventFilter{From: time.Now().UTC().Add(-STATS_PERIOD)})
+	if err != nil {
+		panic(err)
+	}
+
+	banned, err := tg.CountChatBans(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	text := tg.text(ctx, msg, "stats.summary", i18n.Args{
+		"active":    active,
+		"started":   events[datasource.SUBSCRIPTION_STARTED],
+		"stopped":   events[datasource.SUBSCRIPTION_STOPPED],
+		"blocked":   events[datasource.SUBSCRIPTION_BLOCKED],
+		"unblocked": events[datasource.SUBSCRIPTION_UNBLOCKED],
+		"banned":    banned,
+	})
+	tg.SendNormalChat(ctx, msg.Chat.ID, text, "StatsCommand")
+}
+
+// List registered users by chat ID, `/users {after}` shows the page after
+// chat ID `after`
+func (tg *TelegramBotService) UsersCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	var after int64
+	if args := strings.Fields(msg.CommandArguments()); len(args) != 0 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "users.usage", nil), "UsersCommand.usage")
+			return
+		}
+		after = id
+	}
+
+	// fetch one extra row to know whether there is a next page
+	chats, err := tg.GetPrivateChatPage(ctx, datasource.NewQueryFilter(), after, LIST_PAGE_SIZE+1)
+	if err != nil {
+		panic(err)
+	}
+
+	if len(chats) == 0 {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "users.empty", nil), "UsersCommand.empty")
+		return
+	}
+
+	hasNext := len(chats) > LIST_PAGE_SIZE
+	if hasNext {
+		chats = chats[:LIST_PAGE_SIZE]
+	}
+
+	lines := make([]string, 0, len(chats)+1)
+	for i := range chats {
+		line := fmt.Sprintf("%d %s", chats[i].ChatID, strings.TrimSpace(chats[i].Name))
+		if len(chats[i].Username) != 0 {
+			line += " @" + chats[i].Username
+		}
+		if chats[i].IsBlocked {
+			line += " " + tg.text(ctx, msg, "users.blocked", nil)
+		}
+		lines = append(lines, line)
+	}
+
+	if hasNext {
+		lines = append(lines, "", tg.text(ctx, msg, "users.next", i18n.Args{"after": chats[len(chats)-1].ChatID}))
+	}
+
+	tg.SendNormalChat(ctx, msg.Chat.ID, strings.Join(lines, "\n"), "UsersCommand")
+}
+
+// Send text to every active user who isn't banned. Sending is throttled, so
+// it runs in the background & reports to the sender when done. Only one
+// broadcast runs at a time, it stops on shutdown.
+func (tg *TelegramBotService) BroadcastCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	text := strings.TrimSpace(msg.CommandArguments())
+	if len(text) == 0 {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "broadcast.usage", nil), "BroadcastCommand.usage")
+		return
+	}
+
+	// several broadcasts would multiply the send rate
+	if !tg.broadcasting.CompareAndSwap(false, true) {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "broadcast.running", nil), "BroadcastCommand.running")
+		return
+	}
+
+	active, err := tg.CountActivePrivateChat(ctx)
+	if err != nil {
+		tg.broadcasting.Store(false)
+		panic(err)
+	}
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "broadcast.started", i18n.Args{"count": active}), "BroadcastCommand.started")
+
+	// outlives the update's timeout, keeps its logger & locale
+	bctx := log.Ctx(ctx).WithContext(context.WithValue(tg.appCtx, localeKey{}, tg.locale(ctx, msg)))
+	// not run by HandleUpdate, a panic here would take the whole bot down
+	go func() {
+		defer tg.broadcasting.Store(false)
+		defer func() {
+			if v := recover(); v != nil {
+				tg.handlePanic(bctx, fmt.Errorf("broadcast: %v", v), tgbotapi.Update{Message: msg})
+			}
+		}()
+
+		tg.broadcast(bctx, msg, text)
+	}()
+}
+
+func (tg *TelegramBotService) broadcast(ctx context.Context, msg *tgbotapi.Message, text string) {
+	ctx, span := tracing.Start(ctx, "broadcast")
+	defer span.End()
+
+	ticker := time.NewTicker(BROADCAST_INTERVAL)
+	defer ticker.Stop()
+
+	var (
+		after                 int64
+		sent, failed, skipped int
+	)
+
+pages:
+	for {
+		chats, err := tg.GetPrivateChatPage(ctx, datasource.NewQueryFilter(), after, LIST_PAGE_SIZE)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("sent", sent).Msg("broadcast.database")
+			break
+		}
+
+		for i := range chats {
+			if chats[i].IsBlocked {
+				continue
+			}
+
+			switch banned, err := tg.IsChatBanned(ctx, chats[i].ChatID); {
+			case err != nil:
+				log.Ctx(ctx).Error().Err(err).Int64("target", chats[i].ChatID).Msg("broadcast.ban")
+				failed++
+				continue
+			case banned:
+				skipped++
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Ctx(ctx).Warn().Int("sent", sent).Msg("broadcast.canceled")
+				break pages
+			case <-ticker.C:
+			}
+
+			if tg.SendChat(ctx, chats[i].ChatID, text, render.PARSE_MODE_PLAIN, "broadcast") {
+				sent++
+			} else {
+				failed++
+			}
+		}
+
+		if len(chats) < LIST_PAGE_SIZE {
+			break
+		}
+		after = chats[len(chats)-1].ChatID
+	}
+
+	log.Ctx(ctx).Info().Int("sent", sent).Int("failed", failed).Int("skipped", skipped).Msg("broadcast.result")
+
+	// shutting down, the report can't be sent anymore
+	if ctx.Err() != nil {
+		return
+	}
+
+	report := tg.text(ctx, msg, "broadcast.done", i18n.Args{"sent": sent, "failed": failed})
+	tg.SendNormalChat(ctx, msg.Chat.ID, report, "broadcast.done")
+}
+
+// `/ban {chat_id} {reason}` drops every update of the chat from now on
+func (tg *TelegramBotService) BanCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	args := strings.Fields(msg.CommandArguments())
+	chatId, ok := parseChatId(args)
+	if !ok {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "ban.usage", nil), "BanCommand.usage")
+		return
+	}
+
+	// nobody can lock out someone with a role as high as theirs: admins
+	// can't be banned, moderators only by admins
+	if tg.roleRank(ctx, chatId) >= tg.roleRank(ctx, msg.Chat.ID) {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "ban.protected", nil), "BanCommand.protected")
+		return
+	}
+
+	ban := &datasource.ChatBan{
+		ChatID:   chatId,
+		Reason:   strings.Join(args[1:], " "),
+		BannedBy: msg.Chat.ID,
+	}
+	if err := tg.BanChat(ctx, ban); err != nil {
+		panic(err)
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Msg("chat.banned")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "ban.done", i18n.Args{"chat_id": chatId}), "BanCommand")
+}
+
+func (tg *TelegramBotService) UnbanCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	chatId, ok := parseChatId(strings.Fields(msg.CommandArguments()))
+	if !ok {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "unban.usage", nil), "UnbanCommand.usage")
+		return
+	}
+
+	unbanned, err := tg.UnbanChat(ctx, chatId)
+	if err != nil {
+		panic(err)
+	}
+
+	key := "unban.done"
+	if !unbanned {
+		key = "unban.not_banned"
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Bool("unbanned", unbanned).Msg("chat.unbanned")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, key, i18n.Args{"chat_id": chatId}), "UnbanCommand")
+}
+
+// List bans by chat ID, `/bans {after}` shows the page after chat ID `after`
+func (tg *TelegramBotService) BansCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	var after int64
+	if args := strings.Fields(msg.CommandArguments()); len(args) != 0 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "bans.usage", nil), "BansCommand.usage")
+			return
+		}
+		after = id
+	}
+
+	// fetch one extra row to know whether there is a next page
+	bans, err := tg.GetChatBans(ctx, after, LIST_PAGE_SIZE+1)
+	if err != nil {
+		panic(err)
+	}
+
+	if len(bans) == 0 {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "bans.empty", nil), "BansCommand.empty")
+		return
+	}
+
+	hasNext := len(bans) > LIST_PAGE_SIZE
+	if hasNext {
+		bans = bans[:LIST_PAGE_SIZE]
+	}
+
+	lines := make([]string, 0, len(bans)+1)
+	for i := range bans {
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf("%d %s", bans[i].ChatID, bans[i].Reason)))
+	}
+
+	if hasNext {
+		lines = append(lines, "", tg.text(ctx, msg, "bans.next", i18n.Args{"after": bans[len(bans)-1].ChatID}))
+	}
+
+	tg.SendNormalChat(ctx, msg.Chat.ID, strings.Join(lines, "\n"), "BansCommand")
+}
+
+// `/allow {chat_id}` lets the chat use the bot in allowlist-only mode
+func (tg *TelegramBotService) AllowCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	chatId, ok := parseChatId(strings.Fields(msg.CommandArguments()))
+	if !ok {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "allow.usage", nil), "AllowCommand.usage")
+		return
+	}
+
+	if err := tg.AllowChat(ctx, &datasource.ChatAllow{ChatID: chatId, AllowedBy: msg.Chat.ID}); err != nil {
+		panic(err)
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Msg("chat.allowed")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "allow.done", i18n.Args{"chat_id": chatId}), "AllowCommand")
+}
+
+func (tg *TelegramBotService) DisallowCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	chatId, ok := parseChatId(strings.Fields(msg.CommandArguments()))
+	if !ok {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "disallow.usage", nil), "DisallowCommand.usage")
+		return
+	}
+
+	removed, err := tg.DisallowChat(ctx, chatId)
+	if err != nil {
+		panic(err)
+	}
+
+	key := "disallow.done"
+	if !removed {
+		key = "disallow.not_allowed"
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Bool("removed", removed).Msg("chat.disallowed")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, key, i18n.Args{"chat_id": chatId}), "DisallowCommand")
+}
+
+// `/grant {chat_id} {role}`
+func (tg *TelegramBotService) GrantCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	chatId, role, ok := tg.parseRoleArgs(ctx, msg)
+	if !ok {
+		return
+	}
+
+	if err := tg.GrantChatRole(ctx, &datasource.ChatRole{ChatID: chatId, Role: role, GrantedBy: msg.Chat.ID}); err != nil {
+		panic(err)
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Str("role", role).Msg("role.granted")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "role.granted", i18n.Args{"chat_id": chatId, "role": role}), "GrantCommand")
+}
+
+// `/revoke {chat_id} {role}`, admins from config keep their role
+func (tg *TelegramBotService) RevokeCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+	chatId, role, ok := tg.parseRoleArgs(ctx, msg)
+	if !ok {
+		return
+	}
+
+	revoked, err := tg.RevokeChatRole(ctx, chatId, role)
+	if err != nil {
+		panic(err)
+	}
+
+	key := "role.revoked"
+	if !revoked {
+		key = "role.not_granted"
+	}
+
+	log.Ctx(ctx).Info().Int64("target", chatId).Str("role", role).Bool("revoked", revoked).Msg("role.revoked")
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, key, i18n.Args{"chat_id": chatId, "role": role}), "RevokeCommand")
+}
+
+// replies with usage & returns false if arguments are invalid
+func (tg *TelegramBotService) parseRoleArgs(ctx context.Context, msg *tgbotapi.Message) (chatId int64, role string, ok bool) {
+	roles := strings.Join(datasource.ROLES, ", ")
+
+	args := strings.Fields(msg.CommandArguments())
+	chatId, ok = parseChatId(args)
+	if !ok || len(args) != 2 {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "role.usage", i18n.Args{"roles": roles}), "parseRoleArgs.usage")
+		return 0, "", false
+	}
+
+	role = strings.ToLower(args[1])
+	if !datasource.IsRole(role) {
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "role.unknown", i18n.Args{"role": args[1], "roles": roles}), "parseRoleArgs.unknown")
+		return 0, "", false
+	}
+
+	return chatId, role, true
+}
+
+// first argument as private chat ID
+func parseChatId(args []string) (int64, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return id, true
+}
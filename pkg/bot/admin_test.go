@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+)
+
+// fake Bot API, records texts of sent messages
+type fakeTelegram struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:] {
+	case "getMe":
+		fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"bidoof"}}`)
+
+	case "sendMessage":
+		f.mu.Lock()
+		f.sent = append(f.sent, r.FormValue("text"))
+		f.mu.Unlock()
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":%s,"type":"private"}}}`, r.FormValue("chat_id"))
+
+	default:
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}
+}
+
+func (f *fakeTelegram) Sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.sent...)
+}
+
+// bot with the repo's catalogs, a fake Bot API & a mocked database. Redis is
+// off so every lookup reaches the mock.
+func newTestBot(t *testing.T, admins ...int64) (*TelegramBotService, sqlmock.Sqlmock, *fakeTelegram) {
+	telegram := new(fakeTelegram)
+	server := httptest.NewServer(telegram)
+	t.Cleanup(server.Close)
+
+	botApi, err := tgbotapi.NewBotAPIWithClient("123:abc", server.URL+"/bot%s/%s", server.Client())
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var cfg config.AppConfig
+	cfg.Telegram.Admins = admins
+	cfg.Telegram.Bot.Timeout = config.Duration(5 * time.Second)
+	cfg.I18n.Dir = "../../setting/locales"
+	cfg.I18n.TemplateDir = "../../setting/templates"
+	cfg.I18n.DefaultLocale = "en"
+
+	ds := datasource.NewDataSource(config.NewStore(cfg, config.Options{}), sqlx.NewDb(db, "mysql"), nil)
+	tg := &TelegramBotService{DataSource: ds, BotAPI: botApi, appCtx: context.Background()}
+	require.NoError(t, tg.LoadMessages(&cfg))
+
+	return tg, mock, telegram
+}
+
+// context with the locale already resolved, like while handling an update
+func testContext() context.Context {
+	return context.WithValue(context.Background(), localeKey{}, "en")
+}
+
+func commandMessage(chatId int64, text string) *tgbotapi.Message {
+	command := strings.Fields(text)[0]
+
+	return &tgbotapi.Message{
+		Chat:     &tgbotapi.Chat{ID: chatId, Type: "private"},
+		From:     &tgbotapi.User{ID: chatId},
+		Text:     text,
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+	}
+}
+
+func expectRoles(mock sqlmock.Sqlmock, chatId int64, roles ...string) {
+	rows := sqlmock.NewRows([]string{"role"})
+	for _, role := range roles {
+		rows.AddRow(role)
+	}
+	mock.ExpectQuery("telegram_chat_role").WithArgs(chatId).WillReturnRows(rows)
+}
+
+func TestParseChatId(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Args []string
+		Id   int64
+		Ok   bool
+	}{
+		{"valid", []string{"1234", "spam"}, 1234, true},
+		{"empty", nil, 0, false},
+		{"not_number", []string{"@gabriel_s"}, 0, false},
+		{"group", []string{"-1001234"}, 0, false},
+		{"zero", []string{"0"}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			id, ok := parseChatId(tc.Args)
+			assert.Equal(t, tc.Id, id)
+			assert.Equal(t, tc.Ok, ok)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tg, mock, telegram := newTestBot(t, 1)
+	forbidden := tg.Catalog().T("en", "error.forbidden", nil)
+
+	var ran []int64
+	cmd := func(ctx context.Context, msg *tgbotapi.Message, _ []string) {
+		ran = append(ran, msg.Chat.ID)
+	}
+	moderatorCmd := tg.requireRole(datasource.ROLE_MODERATOR, cmd)
+	adminCmd := tg.requireRole(datasource.ROLE_ADMIN, cmd)
+
+	// admin from config, no lookup
+	adminCmd(testContext(), commandMessage(1, "/grant"), nil)
+
+	// granted moderator
+	expectRoles(mock, 2, datasource.ROLE_MODERATOR)
+	moderatorCmd(testContext(), commandMessage(2, "/ban"), nil)
+	expectRoles(mock, 2, datasource.ROLE_MODERATOR)
+	adminCmd(testContext(), commandMessage(2, "/grant"), nil)
+
+	// granted admin has every role
+	expectRoles(mock, 3, datasource.ROLE_ADMIN)
+	moderatorCmd(testContext(), commandMessage(3, "/ban"), nil)
+
+	// regular user
+	expectRoles(mock, 4)
+	moderatorCmd(testContext(), commandMessage(4, "/ban"), nil)
+
+	assert.Equal(t, []int64{1, 2, 3}, ran)
+	assert.Equal(t, []string{forbidden, forbidden}, telegram.Sent())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// failing lookup must not let the command through
+	mock.ExpectQuery("telegram_chat_role").WillReturnError(errors.New("connection refused"))
+	assert.Panics(t, func() { moderatorCmd(testContext(), commandMessage(5, "/ban"), nil) })
+	assert.Equal(t, []int64{1, 2, 3}, ran)
+}
+
+func TestParseRoleArgs(t *testing.T) {
+	tg, _, telegram := newTestBot(t)
+	roles := strings.Join(datasource.ROLES, ", ")
+
+	chatId, role, ok := tg.parseRoleArgs(testContext(), commandMessage(1, "/grant 1234 Moderator"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), chatId)
+	assert.Equal(t, datasource.ROLE_MODERATOR, role)
+	assert.Empty(t, telegram.Sent())
+
+	testCases := []struct {
+		Name  string
+		Text  string
+		Reply string
+	}{
+		{"no_role", "/grant 1234", tg.Catalog().T("en", "role.usage", map[string]any{"roles": roles})},
+		{"no_chat_id", "/grant moderator", tg.Catalog().T("en", "role.usage", map[string]any{"roles": roles})},
+		{"unknown_role", "/grant 1234 owner", tg.Catalog().T("en", "role.unknown", map[string]any{"role": "owner", "roles": roles})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			before := len(telegram.Sent())
+
+			_, _, ok := tg.parseRoleArgs(testContext(), commandMessage(1, tc.Text))
+			assert.False(t, ok)
+			if sent := telegram.Sent(); assert.Len(t, sent, before+1) {
+				assert.Equal(t, strings.TrimSpace(tc.Reply), strings.TrimSpace(sent[before]))
+			}
+		})
+	}
+}
+
+func TestBanCommand(t *testing.T) {
+	tg, mock, telegram := newTestBot(t, 1)
+	protected := tg.Catalog().T("en", "ban.protected", nil)
+	banned := func(chatId int64) string {
+		return tg.Catalog().T("en", "ban.done", map[string]any{"chat_id": chatId})
+	}
+
+	// a role lookup per role checked, config admin needs none
+	expectRank := func(chatId int64, roles ...string) {
+		expectRoles(mock, chatId, roles...)
+		if len(roles) == 0 || roles[0] != datasource.ROLE_ADMIN {
+			expectRoles(mock, chatId, roles...)
+		}
+	}
+
+	testCases := []struct {
+		Name   string
+		Caller int64
+		Target int64
+		Expect func()
+		Reply  string
+	}{
+		{"moderator_bans_moderator", 2, 3, func() {
+			expectRank(3, datasource.ROLE_MODERATOR)
+			expectRank(2, datasource.ROLE_MODERATOR)
+		}, protected},
+		{"moderator_bans_admin", 2, 1, func() {
+			expectRank(2, datasource.ROLE_MODERATOR)
+		}, protected},
+		{"moderator_bans_granted_admin", 2, 5, func() {
+			expectRank(5, datasource.ROLE_ADMIN)
+			expectRank(2, datasource.ROLE_MODERATOR)
+		}, protected},
+		{"admin_bans_moderator", 1, 3, func() {
+			expectRank(3, datasource.ROLE_MODERATOR)
+			mock.ExpectExec("telegram_chat_ban").WithArgs(3, "spam", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		}, banned(3)},
+		{"moderator_bans_user", 2, 4, func() {
+			expectRank(4)
+			expectRank(2, datasource.ROLE_MODERATOR)
+			mock.ExpectExec("telegram_chat_ban").WithArgs(4, "spam", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		}, banned(4)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Expect()
+			before := len(telegram.Sent())
+
+			tg.BanCommand(testContext(), commandMessage(tc.Caller, fmt.Sprintf("/ban %d spam", tc.Target)), nil)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+			if sent := telegram.Sent(); assert.Len(t, sent, before+1) {
+				assert.Equal(t, tc.Reply, sent[before])
+			}
+		})
+	}
+}
@@ -3,12 +3,22 @@ package bot
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/async"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/i18n"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/logging"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/metrics"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -20,11 +30,23 @@ type TelegramBotService struct {
 
 	BotAPI   *tgbotapi.BotAPI
 	Commands map[string]Command
+
+	// swapped on config reload
+	catalog  atomic.Pointer[i18n.Catalog]
+	renderer atomic.Pointer[render.Renderer]
+
+	// app context, bounds work outliving an update (e.g. broadcasts)
+	appCtx context.Context
+
+	// only one broadcast runs at a time
+	broadcasting atomic.Bool
 }
 
-func NewTelegramBotService(bot *tgbotapi.BotAPI, ds *datasource.DataSource) *TelegramBotService {
+// `ctx` is the app context, background work is canceled with it on shutdown
+func NewTelegramBotService(ctx context.Context, bot *tgbotapi.BotAPI, ds *datasource.DataSource) *TelegramBotService {
 	tg := new(TelegramBotService)
 
+	tg.appCtx = ctx
 	tg.DataSource = ds
 	tg.BotAPI = bot
 	tg.InitBot()
@@ -32,63 +54,173 @@ func NewTelegramBotService(bot *tgbotapi.BotAPI, ds *datasource.DataSource) *Tel
 	return tg
 }
 
+func (tg *TelegramBotService) Catalog() *i18n.Catalog {
+	return tg.catalog.Load()
+}
+
+func (tg *TelegramBotService) Renderer() *render.Renderer {
+	return tg.renderer.Load()
+}
+
 // handle update in separate goroutine so I can implement task timeouts
 func (tg *TelegramBotService) HandleUpdate(event tgbotapi.Update) {
-	updateCtx, cancel := context.WithTimeout(context.Background(), time.Duration(tg.Config.Telegram.Bot.Timeout)*time.Second)
-	defer cancel()
-	updateDone := make(chan struct{})
-	commandPanic := make(chan error)
-
-	// update handler goroutine with recovery function
-	go func() {
-		defer func() {
-			// same as app level recovery, this handle command panics
-			// and reports it to sender & logfile
-			if err := recover(); err != nil {
-				commandPanic <- fmt.Errorf("%v", err)
-			} else {
-				// normal flow
-				updateDone <- struct{}{}
-			}
-		}()
+	timeout := tg.Config.Get().Telegram.Bot.Timeout.Duration()
+
+	updateType, command := tg.updateLabels(event)
+	metrics.UpdatesTotal.WithLabelValues(updateType, command).Inc()
+	defer metrics.ObserveDuration(metrics.UpdateDuration.WithLabelValues(updateType), time.Now())
+
+	// every entry logged while handling this update carries its IDs
+	ctx := logging.With(context.Background(), func(c zerolog.Context) zerolog.Context {
+		return updateLogFields(c, event)
+	})
 
-		// check if its a command, otherwise do nothing
-		if event.Message.IsCommand() {
-			tg.handleCommand(updateCtx, event.Message)
+	// root of the update's trace, everything below is a child span
+	ctx, span := tracing.Start(ctx, "update."+updateType, updateAttributes(event)...)
+
+	_, err := async.Run(ctx, timeout, func(ctx context.Context) (struct{}, error) {
+		// denied users are ignored before anything else, even /start
+		if reason := tg.denyReason(ctx, event); len(reason) != 0 {
+			log.Ctx(ctx).Info().Str("reason", reason).Msg("update.denied")
+			return struct{}{}, nil
 		}
-	}()
 
-	// wait for results
-	for {
-		select {
-		case <-updateCtx.Done():
-			if updateCtx.Err() == context.DeadlineExceeded {
-				fmt.Println("timeout exceeded")
-			} else {
-				fmt.Println("Normal cancellation")
+		switch {
+		// user blocked / unblocked the bot
+		case event.MyChatMember != nil:
+			tg.handleMyChatMember(ctx, event.MyChatMember)
+
+		case event.Message != nil:
+			tg.recordMessage(ctx, event.Message, datasource.MESSAGE_INBOUND)
+
+			if event.Message.Chat.IsPrivate() {
+				tg.touchPrivateChat(ctx, event.Message)
 			}
-			return
 
-		case <-updateDone:
-			return
+			// check if its a command, otherwise do nothing
+			if event.Message.IsCommand() {
+				tg.handleCommand(tg.withLocale(ctx, event.Message), event.Message)
+			}
+		}
 
-		case err := <-commandPanic:
-			tg.handlePanic(err, event.Message)
-			return
+		return struct{}{}, nil
+	})
+
+	// same as app level recovery, this handle command panics and reports it
+	// to sender & logfile
+	var panicErr *async.PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		metrics.UpdatePanics.Inc()
+		tg.handlePanic(ctx, fmt.Errorf("%v", panicErr.Value), event)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		metrics.UpdateTimeouts.Inc()
+		log.Ctx(ctx).Warn().Dur("timeout", timeout).Msg("update.timeout")
+
+	case err != nil:
+		log.Ctx(ctx).Warn().Err(err).Msg("update.canceled")
+	}
+
+	tracing.End(span, err)
+}
+
+// why updates of the sender are dropped, empty if they are not: banned, or
+// not in the allowlist in allowlist-only mode. Admins & moderators don't need
+// to be in the allowlist. Lookups are cached, if one fails the update is
+// dropped too since the sender can't be checked.
+func (tg *TelegramBotService) denyReason(ctx context.Context, event tgbotapi.Update) string {
+	user := event.SentFrom()
+	if user == nil {
+		return ""
+	}
+
+	banned, err := tg.IsChatBanned(ctx, user.ID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("check", "ban").Msg("update.lookup")
+		return "lookup_failed"
+	}
+	if banned {
+		return "banned"
+	}
+
+	if !tg.Config.Get().Telegram.AllowlistOnly {
+		return ""
+	}
+
+	moderator, err := tg.HasChatRole(ctx, user.ID, datasource.ROLE_MODERATOR)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("check", "role").Msg("update.lookup")
+		return "lookup_failed"
+	}
+	if moderator {
+		return ""
+	}
+
+	allowed, err := tg.IsChatAllowed(ctx, user.ID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("check", "allowlist").Msg("update.lookup")
+		return "lookup_failed"
+	}
+	if !allowed {
+		return "not_allowed"
+	}
+
+	return ""
+}
+
+func updateLogFields(c zerolog.Context, event tgbotapi.Update) zerolog.Context {
+	c = c.Int("update_id", event.UpdateID)
+	if chat := event.FromChat(); chat != nil {
+		c = c.Int64("chat_id", chat.ID)
+	}
+	if event.Message != nil && event.Message.IsCommand() {
+		c = c.Str("command", event.Message.Command())
+	}
+
+	return c
+}
+
+func updateAttributes(event tgbotapi.Update) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.Int("update_id", event.UpdateID)}
+	if chat := event.FromChat(); chat != nil {
+		attrs = append(attrs, attribute.Int64("chat_id", chat.ID))
+	}
+
+	return attrs
+}
+
+// metric labels of an update, commands are limited to the registered ones so
+// users can't blow up label cardinality
+func (tg *TelegramBotService) updateLabels(event tgbotapi.Update) (updateType, command string) {
+	switch {
+	case event.MyChatMember != nil:
+		return "my_chat_member", ""
+
+	case event.Message != nil && event.Message.IsCommand():
+		if _, exist := tg.Commands[event.Message.Command()]; exist {
+			return "command", event.Message.Command()
 		}
+		return "command", "unknown"
+
+	case event.Message != nil:
+		return "message", ""
+
+	default:
+		return "other", ""
 	}
 }
 
 func (tg *TelegramBotService) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
 	// check is private chat
 	if !msg.Chat.IsPrivate() {
-		tg.SendNormalChat(msg.Chat.ID, tg.Config.Telegram.Bot.Messages.GroupChat, "StartCommand.IsPrivate")
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "error.group_chat", nil), "StartCommand.IsPrivate")
 		return
 	}
 
 	// check if this user can send command, but pass it through if it was /start command
 	if msg.Command() != "start" {
-		switch _, err := tg.GetPrivateChat(msg.Chat.ID); {
+		switch _, err := tg.GetPrivateChat(ctx, msg.Chat.ID); {
 
 		// user is not registered in DB: do nothing
 		case err == sql.ErrNoRows:
@@ -103,19 +235,73 @@ func (tg *TelegramBotService) handleCommand(ctx context.Context, msg *tgbotapi.M
 	// check if command exists
 	handler, exist := tg.Commands[msg.Command()]
 	if !exist {
-		log.Warn().Str("command", msg.Command()).Msg("command.error")
+		log.Ctx(ctx).Warn().Msg("command.unknown")
 
 		// inform user it was unknown command
-		tg.SendNormalChat(msg.Chat.ID, tg.Config.Telegram.Bot.Messages.UnknownCommand, "handleCommand")
+		tg.SendNormalChat(ctx, msg.Chat.ID, tg.text(ctx, msg, "error.unknown_command", nil), "handleCommand")
 
 		return
 	}
 
+	ctx, span := tracing.Start(ctx, "command."+msg.Command(), attribute.String("command", msg.Command()))
+	defer span.End()
+
 	handler(ctx, msg, strings.Split(msg.CommandArguments(), " "))
 }
 
-func (tg *TelegramBotService) handlePanic(err error, msg *tgbotapi.Message) {
-	log.Error().Err(err).Interface("message", msg).Msg("command.panic")
-	text := fmt.Sprintf(tg.Config.Telegram.Bot.Messages.Panic)
-	tg.SendNormalChat(msg.Chat.ID, text, "handlePanic")
+// Periodically delete messages older than the configured retention. Blocks
+// until ctx is done, so run it in its own goroutine.
+func (tg *TelegramBotService) PurgeMessageHistory(ctx context.Context) {
+	retention := tg.Config.Get().History.RetentionDays
+	interval := tg.Config.Get().History.PurgeInterval.Duration()
+
+	// keep forever
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		before := time.Now().UTC().AddDate(0, 0, -retention)
+		if n, err := tg.PurgeMessages(ctx, before); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("history.purge")
+		} else {
+			log.Ctx(ctx).Info().Int64("deleted", n).Time("before", before).Msg("history.purge")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Telegram sends my_chat_member update with "kicked" status when a user
+// blocks the bot in private chat, and "member" when unblocked
+func (tg *TelegramBotService) handleMyChatMember(ctx context.Context, update *tgbotapi.ChatMemberUpdated) {
+	if !update.Chat.IsPrivate() {
+		return
+	}
+
+	blocked := update.NewChatMember.WasKicked()
+	if err := tg.SetPrivateChatBlocked(ctx, update.Chat.ID, blocked); err != nil {
+		panic(err)
+	}
+
+	log.Ctx(ctx).Info().Bool("blocked", blocked).Msg("chat.blocked")
+}
+
+func (tg *TelegramBotService) handlePanic(ctx context.Context, err error, event tgbotapi.Update) {
+	log.Ctx(ctx).Error().Err(err).Interface("event", event).Msg("command.panic")
+
+	// only inform the user if there's a message to reply to
+	if event.Message == nil {
+		return
+	}
+
+	text := tg.text(ctx, event.Message, "error.panic", nil)
+	tg.SendNormalChat(ctx, event.Message.Chat.ID, text, "handlePanic")
 }
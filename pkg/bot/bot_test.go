@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+)
+
+func TestDenyReason(t *testing.T) {
+	tg, mock, _ := newTestBot(t)
+	update := func(chatId int64) tgbotapi.Update {
+		return tgbotapi.Update{Message: commandMessage(chatId, "/start")}
+	}
+
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(1).WillReturnRows(mock.NewRows([]string{"chat_id"}).AddRow(1))
+	assert.Equal(t, "banned", tg.denyReason(testContext(), update(1)))
+
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(2).WillReturnRows(mock.NewRows([]string{"chat_id"}))
+	assert.Equal(t, "", tg.denyReason(testContext(), update(2)))
+
+	// allowlist-only, moderators don't need to be allowed
+	tg.Config.Get().Telegram.AllowlistOnly = true
+
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(3).WillReturnRows(mock.NewRows([]string{"chat_id"}))
+	expectRoles(mock, 3)
+	mock.ExpectQuery("telegram_chat_allow").WithArgs(3).WillReturnRows(mock.NewRows([]string{"1"}))
+	assert.Equal(t, "not_allowed", tg.denyReason(testContext(), update(3)))
+
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(4).WillReturnRows(mock.NewRows([]string{"chat_id"}))
+	expectRoles(mock, 4, datasource.ROLE_MODERATOR)
+	assert.Equal(t, "", tg.denyReason(testContext(), update(4)))
+
+	// database down drops the update instead of panicking
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(5).WillReturnError(errors.New("connection refused"))
+	assert.Equal(t, "lookup_failed", tg.denyReason(testContext(), update(5)))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
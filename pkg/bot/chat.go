@@ -1,21 +1,66 @@
 package bot
 
 import (
+	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
 )
 
-func (tg *TelegramBotService) SendNormalChat(chatId int64, text, logSubject string) {
-	msg := tgbotapi.NewMessage(chatId, text)
-	if _, err := tg.BotAPI.Send(msg); err != nil {
-		log.Error().Err(err).Interface("message", msg).Msg("send.error-" + logSubject)
+// send `text` as is, it must already be valid for `parseMode`. Text over
+// Telegram's limit is sent as several messages. Returns false if sending
+// failed.
+func (tg *TelegramBotService) SendChat(ctx context.Context, chatId int64, text string, parseMode render.ParseMode, logSubject string) bool {
+	chunks, err := render.Split(parseMode, text, render.MAX_MESSAGE_LENGTH)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Int64("chat_id", chatId).Str("subject", logSubject).Msg("send.split")
+		return false
 	}
+
+	for _, chunk := range chunks {
+		msg := tgbotapi.NewMessage(chatId, chunk)
+		msg.ParseMode = string(parseMode)
+		if !tg.send(ctx, msg, logSubject) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tg *TelegramBotService) SendNormalChat(ctx context.Context, chatId int64, text, logSubject string) {
+	tg.SendChat(ctx, chatId, text, render.PARSE_MODE_PLAIN, logSubject)
+}
+
+func (tg *TelegramBotService) SendMarkdownChat(ctx context.Context, chatId int64, text, logSubject string) {
+	tg.SendChat(ctx, chatId, text, render.PARSE_MODE_MARKDOWN_V2, logSubject)
 }
 
-func (tg *TelegramBotService) SendMarkdownChat(chatId int64, text, logSubject string) {
-	msg := tgbotapi.NewMessage(chatId, text)
-	msg.ParseMode = tgbotapi.ModeMarkdownV2
-	if _, err := tg.BotAPI.Send(msg); err != nil {
-		log.Error().Err(err).Interface("message", msg).Msg("send.error-" + logSubject)
+// render template `name` in the sender's language and send it with the
+// template's parse mode
+func (tg *TelegramBotService) SendTemplateChat(ctx context.Context, msg *tgbotapi.Message, name string, data any, logSubject string) {
+	text, parseMode, err := tg.Renderer().Render(tg.locale(ctx, msg), name, data)
+	if err != nil {
+		panic(err)
 	}
+
+	tg.SendChat(ctx, msg.Chat.ID, text, parseMode, logSubject)
+}
+
+// returns false if sending failed, remaining chunks of a long message
+// shouldn't be sent then
+func (tg *TelegramBotService) send(ctx context.Context, msg tgbotapi.MessageConfig, logSubject string) bool {
+	sent, err := tracing.TelegramCall(ctx, "sendMessage", func() (tgbotapi.Message, error) {
+		return tg.BotAPI.Send(msg)
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("subject", logSubject).Interface("message", msg).Msg("send.error")
+		return false
+	}
+
+	tg.recordMessage(ctx, &sent, datasource.MESSAGE_OUTBOUND)
+
+	return true
 }
@@ -3,10 +3,10 @@ package bot
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/i18n"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
 )
 
 func (tg *TelegramBotService) UnimplementedCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
@@ -17,21 +17,21 @@ func (tg *TelegramBotService) StartCommand(ctx context.Context, msg *tgbotapi.Me
 	chat := msg.Chat
 
 	// check if user chat id is already registered
-	_, err := tg.GetPrivateChat(chat.ID)
+	_, err := tg.GetPrivateChat(ctx, chat.ID)
 	if err == nil {
-		text := msg.From.FirstName + ", looks like you've already awaken Grand Lord Bidoof!"
-		tg.SendNormalChat(chat.ID, text, "StartCommand.GetPrivateChat")
+		text := tg.text(ctx, msg, "start.already_started", i18n.Args{"name": msg.From.FirstName})
+		tg.SendNormalChat(ctx, chat.ID, text, "StartCommand.GetPrivateChat")
 		return
 	}
 
 	switch {
 	// user has not started the bot yet, so register them
 	case err == sql.ErrNoRows:
-		tg.savePrivateChat(chat)
+		tg.savePrivateChat(ctx, msg)
 
 		// inform user
-		text := msg.From.FirstName + ", thank you for waking me. Bidoof bless you."
-		tg.SendNormalChat(chat.ID, text, "StartCommand.savePrivateChat")
+		text := tg.text(ctx, msg, "start.welcome", i18n.Args{"name": msg.From.FirstName})
+		tg.SendNormalChat(ctx, chat.ID, text, "StartCommand.savePrivateChat")
 
 	// system error (db)
 	case err != nil:
@@ -42,12 +42,12 @@ func (tg *TelegramBotService) StartCommand(ctx context.Context, msg *tgbotapi.Me
 func (tg *TelegramBotService) StopCommand(ctx context.Context, msg *tgbotapi.Message, _ []string) {
 	chat := msg.Chat
 
-	switch _, err := tg.GetPrivateChat(chat.ID); {
+	switch _, err := tg.GetPrivateChat(ctx, chat.ID); {
 
 	// no user found in DB, then do nothing
 	case err == sql.ErrNoRows:
-		text := "uh-oh, Who art thou? Zzzzz..."
-		tg.SendNormalChat(chat.ID, text, "StopCommand.GetPrivateChat")
+		text := tg.text(ctx, msg, "stop.unknown_user", nil)
+		tg.SendNormalChat(ctx, chat.ID, text, "StopCommand.GetPrivateChat")
 		return
 
 	// system error (db)
@@ -56,12 +56,11 @@ func (tg *TelegramBotService) StopCommand(ctx context.Context, msg *tgbotapi.Mes
 
 	// user found, then delete the chat record
 	case err == nil:
-		if err := tg.DeletePrivateChat(chat.ID); err != nil {
+		if err := tg.DeletePrivateChat(ctx, chat.ID); err != nil {
 			panic(err)
 		}
 
-		text := fmt.Sprintf(`*Thank you for using me*\! If you need me, you can always /start me again or find me at t\.me/grandlordbidoof\_bot\. You can also safely delete this chat if you want\. Bidoof bless you\.`)
-		tg.SendMarkdownChat(chat.ID, text, "StopCommand.DeletePrivateChat")
+		tg.SendTemplateChat(ctx, msg, "stop_goodbye", nil, "StopCommand.DeletePrivateChat")
 	}
 }
 
@@ -69,30 +68,15 @@ func (tg *TelegramBotService) StopCommand(ctx context.Context, msg *tgbotapi.Mes
 func (tg *TelegramBotService) HelloCommand(ctx context.Context, msg *tgbotapi.Message, args []string) {
 	// validate hello command
 	if len(args) != 2 {
-		tg.showUsage(msg.Chat.ID, HELLO_USAGE, false)
+		tg.showUsage(ctx, msg.Chat.ID, tg.text(ctx, msg, "hello.usage", nil), render.PARSE_MODE_PLAIN)
 		return
 	}
 
-	baseStr := `
-Hello %to% \! Bidoof wants to say: 
-
-"%msg%"
-
-That's all Bidoof have to say, sir\.
-    `
-
-	text := strings.Replace(baseStr, "%to%", args[0], 1)
-	text = strings.Replace(text, "%msg%", args[1], 1)
-
-	tg.SendMarkdownChat(msg.Chat.ID, text, "HelloCommand")
+	// user input is escaped by the template
+	data := struct{ To, Msg string }{args[0], args[1]}
+	tg.SendTemplateChat(ctx, msg, "hello", data, "HelloCommand")
 }
 
-func (tg *TelegramBotService) showUsage(chatId int64, usage string, useMarkdown bool) {
-	if useMarkdown {
-		tg.SendMarkdownChat(chatId, usage, "HelloCommand.showUsage")
-	} else {
-		tg.SendNormalChat(chatId, usage, "HelloCommand.showUsage")
-	}
-
-	return
+func (tg *TelegramBotService) showUsage(ctx context.Context, chatId int64, usage string, parseMode render.ParseMode) {
+	tg.SendChat(ctx, chatId, usage, parseMode, "HelloCommand.showUsage")
 }
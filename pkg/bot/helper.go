@@ -1,20 +1,44 @@
 package bot
 
 import (
+	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
 )
 
 // save incoming private chat
-func (tg *TelegramBotService) savePrivateChat(chat *tgbotapi.Chat) {
-	newChat := &datasource.PrivateChat{
-		ChatID:   chat.ID,
-		Username: chat.UserName,
-		Name:     chat.FirstName + " " + chat.LastName,
-		Bio:      chat.Bio,
+func (tg *TelegramBotService) savePrivateChat(ctx context.Context, msg *tgbotapi.Message) {
+	if err := tg.InsertPrivateChatToDB(ctx, privateChatFromMessage(msg)); err != nil {
+		panic(err)
 	}
+}
 
-	if err := tg.InsertPrivateChatToDB(newChat); err != nil {
+// refresh the sender's profile from every incoming private message
+func (tg *TelegramBotService) touchPrivateChat(ctx context.Context, msg *tgbotapi.Message) {
+	if err := tg.TouchPrivateChat(ctx, privateChatFromMessage(msg)); err != nil {
 		panic(err)
 	}
 }
+
+func privateChatFromMessage(msg *tgbotapi.Message) *datasource.PrivateChat {
+	chat := &datasource.PrivateChat{
+		ChatID:   msg.Chat.ID,
+		Username: msg.Chat.UserName,
+		Name:     msg.Chat.FirstName + " " + msg.Chat.LastName,
+		Bio:      msg.Chat.Bio,
+	}
+
+	if msg.From != nil {
+		chat.LanguageCode = msg.From.LanguageCode
+	}
+
+	return chat
+}
+
+// append message to history, failing to record must not break the reply
+func (tg *TelegramBotService) recordMessage(ctx context.Context, msg *tgbotapi.Message, direction string) {
+	if err := tg.InsertMessage(ctx, datasource.NewMessage(msg, direction)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("direction", direction).Msg("history.insert")
+	}
+}
@@ -2,35 +2,98 @@ package bot
 
 import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/i18n"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
 )
 
 func (tg *TelegramBotService) InitBot() {
+	if err := tg.LoadMessages(tg.Config.Get()); err != nil {
+		panic(err)
+	}
 	tg.RegisterCommands()
+
+	// edited catalogs & templates are picked up without restart
+	tg.Config.OnReload(tg.reloadMessages)
+}
+
+// Load message catalog & templates of `cfg`. Templates are parsed & validated
+// here, so a broken template fails at startup (or fails the reload) instead of
+// when a user triggers it. Both are replaced only if both load.
+func (tg *TelegramBotService) LoadMessages(cfg *config.AppConfig) error {
+	apply, err := tg.reloadMessages(cfg)
+	if err != nil {
+		return err
+	}
+	apply()
+
+	return nil
+}
+
+// Config reload hook, see LoadMessages
+func (tg *TelegramBotService) reloadMessages(cfg *config.AppConfig) (func(), error) {
+	catalog, err := i18n.LoadCatalog(cfg.I18n.Dir, cfg.I18n.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer, err := render.LoadTemplates(cfg.I18n.TemplateDir, cfg.I18n.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		tg.catalog.Store(catalog)
+		tg.renderer.Store(renderer)
+	}, nil
 }
 
 func (tg *TelegramBotService) RegisterCommands() {
 	tg.Commands = map[string]Command{
-		"hello": tg.HelloCommand,
-		"start": tg.StartCommand,
-		"stop":  tg.StopCommand,
-	}
-
-	cmdRegister := []tgbotapi.BotCommand{
-		{
-			Command:     "hello",
-			Description: "Say something",
-		},
-		{
-			Command:     "start",
-			Description: "Start the bot",
-		},
-		{
-			Command:     "stop",
-			Description: "Stop bot interaction for this user",
-		},
-	}
-
-	setBotCmd := tgbotapi.NewSetMyCommandsWithScope(tgbotapi.BotCommandScope{Type: "all_private_chats"}, cmdRegister...)
+		"hello":    tg.HelloCommand,
+		"start":    tg.StartCommand,
+		"stop":     tg.StopCommand,
+		"language": tg.LanguageCommand,
+
+		// operator commands, not shown in the menu
+		"stats":     tg.requireRole(datasource.ROLE_MODERATOR, tg.StatsCommand),
+		"users":     tg.requireRole(datasource.ROLE_MODERATOR, tg.UsersCommand),
+		"ban":       tg.requireRole(datasource.ROLE_MODERATOR, tg.BanCommand),
+		"unban":     tg.requireRole(datasource.ROLE_MODERATOR, tg.UnbanCommand),
+		"bans":      tg.requireRole(datasource.ROLE_MODERATOR, tg.BansCommand),
+		"allow":     tg.requireRole(datasource.ROLE_MODERATOR, tg.AllowCommand),
+		"disallow":  tg.requireRole(datasource.ROLE_MODERATOR, tg.DisallowCommand),
+		"broadcast": tg.requireRole(datasource.ROLE_ADMIN, tg.BroadcastCommand),
+		"grant":     tg.requireRole(datasource.ROLE_ADMIN, tg.GrantCommand),
+		"revoke":    tg.requireRole(datasource.ROLE_ADMIN, tg.RevokeCommand),
+	}
+
+	// menu button order, descriptions are taken from catalog `command.<name>`
+	menu := []string{"hello", "start", "stop", "language"}
+	scope := tgbotapi.BotCommandScope{Type: "all_private_chats"}
+
+	// default locale is registered without language code so it is used for
+	// every language that is not in the catalog
+	tg.setMyCommands(tgbotapi.NewSetMyCommandsWithScope(scope, tg.botCommands(tg.Catalog().DefaultLocale(), menu)...))
+	for _, locale := range tg.Catalog().Locales() {
+		tg.setMyCommands(tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, locale, tg.botCommands(locale, menu)...))
+	}
+}
+
+func (tg *TelegramBotService) botCommands(locale string, menu []string) []tgbotapi.BotCommand {
+	var cmdRegister []tgbotapi.BotCommand
+	for _, cmd := range menu {
+		cmdRegister = append(cmdRegister, tgbotapi.BotCommand{
+			Command:     cmd,
+			Description: tg.Catalog().T(locale, "command."+cmd, nil),
+		})
+	}
+
+	return cmdRegister
+}
+
+func (tg *TelegramBotService) setMyCommands(setBotCmd tgbotapi.SetMyCommandsConfig) {
 	if tgResp, err := tg.BotAPI.Request(setBotCmd); err != nil {
 		panic(err)
 	} else {
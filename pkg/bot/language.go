@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/i18n"
+)
+
+// translate catalog message `key` to the language of the message sender
+func (tg *TelegramBotService) text(ctx context.Context, msg *tgbotapi.Message, key string, args i18n.Args) string {
+	return tg.Catalog().T(tg.locale(ctx, msg), key, args)
+}
+
+type localeKey struct{}
+
+// Resolve the locale of the message sender once, so every text() while
+// handling the update reuses it instead of looking up the chat again
+func (tg *TelegramBotService) withLocale(ctx context.Context, msg *tgbotapi.Message) context.Context {
+	return context.WithValue(ctx, localeKey{}, tg.resolveLocale(ctx, msg))
+}
+
+// locale carried by ctx, resolved on the spot for contexts without one (e.g.
+// panic replies)
+func (tg *TelegramBotService) locale(ctx context.Context, msg *tgbotapi.Message) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+
+	return tg.resolveLocale(ctx, msg)
+}
+
+// language chosen with /language takes precedence over the one reported by
+// Telegram. Lookup errors are ignored since this is also used when replying
+// to panics, the catalog falls back to default locale anyway.
+func (tg *TelegramBotService) resolveLocale(ctx context.Context, msg *tgbotapi.Message) string {
+	if chat, err := tg.GetPrivateChat(ctx, msg.Chat.ID); err == nil && len(chat.Language) != 0 {
+		return chat.Language
+	}
+
+	if msg.From != nil {
+		return msg.From.LanguageCode
+	}
+
+	return tg.Catalog().DefaultLocale()
+}
+
+// Show or change the language Bidoof speaks to this user
+func (tg *TelegramBotService) LanguageCommand(ctx context.Context, msg *tgbotapi.Message, args []string) {
+	requested := strings.TrimSpace(args[0])
+
+	// no argument: show current & available languages
+	if len(requested) == 0 {
+		current := tg.Catalog().Match(tg.locale(ctx, msg))
+		if len(current) == 0 {
+			current = tg.Catalog().DefaultLocale()
+		}
+
+		locales := tg.Catalog().Locales()
+		text := strings.Join([]string{
+			tg.text(ctx, msg, "language.current", i18n.Args{"language": current}),
+			tg.Catalog().Plural(current, "language.available", len(locales), i18n.Args{"languages": strings.Join(locales, ", ")}),
+			tg.text(ctx, msg, "language.usage", nil),
+		}, "\n")
+
+		tg.SendNormalChat(ctx, msg.Chat.ID, text, "LanguageCommand.current")
+		return
+	}
+
+	matched := tg.Catalog().Match(requested)
+	if len(matched) == 0 {
+		text := tg.text(ctx, msg, "language.unknown", i18n.Args{"language": requested})
+		tg.SendNormalChat(ctx, msg.Chat.ID, text, "LanguageCommand.unknown")
+		return
+	}
+
+	if err := tg.SetPrivateChatLanguage(ctx, msg.Chat.ID, matched); err != nil {
+		panic(err)
+	}
+
+	// reply in the newly chosen language
+	tg.SendNormalChat(ctx, msg.Chat.ID, tg.Catalog().T(matched, "language.changed", nil), "LanguageCommand.changed")
+}
@@ -1,14 +0,0 @@
-package bot
-
-const HELLO_USAGE = `
-/hello {name} {word}
-
-Make bidoof say {word} to {name}
-You can also use markdown to format your message
-- bold : *text*
-- underline: __text__
-- strikethrough: ~text~
-- url: [text](https://google.com)
-
-You can long press the command in the menu button to paste it into your text box instead of sending it to the bot directly
-`
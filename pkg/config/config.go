@@ -1,84 +1,127 @@
 package config
 
-import (
-	"os"
-	"strings"
-
-	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
-
-	"gopkg.in/yaml.v2"
-)
-
 type AppConfig struct {
 	Grpc     grpcMeta     `yaml:"grpc"`
 	Telegram telegramMeta `yaml:"telegram"`
 	Redis    redisMeta    `yaml:"redis"`
 	DB       databaseMeta `yaml:"db"`
+	History  historyMeta  `yaml:"history"`
+	I18n     i18nMeta     `yaml:"i18n"`
+	Metrics  metricsMeta  `yaml:"metrics"`
+	Tracing  tracingMeta  `yaml:"tracing"`
+	Log      logMeta      `yaml:"log"`
 }
 
 type grpcMeta struct {
-	Listener string `yaml:"listener"`
-	Timeout  int    `yaml:"timeout"`
-	Mode     string `yaml:"mode"`
-	Logfile  string `yaml:"logfile"`
+	Listener string   `yaml:"listener"`
+	Timeout  Duration `yaml:"timeout"`
+
+	// development or production
+	Mode    string `yaml:"mode"`
+	Logfile string `yaml:"logfile"`
 }
 
 type telegramMeta struct {
-	TokenEnv string  `yaml:"token_env"`
-	Bot      botMeta `yaml:"bot"`
+	Token tokenMeta `yaml:"token"`
+
+	// chat IDs that always have the admin role, on top of roles granted
+	// with /grant
+	Admins []int64 `yaml:"admins"`
+
+	// only chats allowed with /allow (and admins & moderators) can use the
+	// bot, updates of others are dropped
+	AllowlistOnly bool `yaml:"allowlist_only"`
+
+	Bot botMeta `yaml:"bot"`
 }
 
-type botMeta struct {
-	Logfile  string     `yaml:"logfile"`
-	Timeout  int        `yaml:"timeout"`
-	Messages botMessage `yaml:"messages"`
+type tokenMeta struct {
+	// file, env, command or mount, see ReadSecret
+	Source string `yaml:"source"`
+	Ref    string `yaml:"ref"`
+
+	// read from source on Load, kept in memory only
+	Value Secret `yaml:"-"`
 }
 
-type botMessage struct {
-	Panic          string `yaml:"panic"`
-	UnknownCommand string `yaml:"unknown_command"`
-	GroupChat      string `yaml:"group_chat"`
+type botMeta struct {
+	Logfile string   `yaml:"logfile"`
+	Timeout Duration `yaml:"timeout"`
 }
 
 type redisMeta struct {
-	Host string `yaml:"host"`
-	Port string `yaml:"port"`
+	Host  string    `yaml:"host"`
+	Port  string    `yaml:"port"`
+	Cache cacheMeta `yaml:"cache"`
+}
+
+type cacheMeta struct {
+	// for registered chats
+	TTL Duration `yaml:"ttl"`
+
+	// for chats that are not registered
+	NegativeTTL Duration `yaml:"negative_ttl"`
 }
 
 type databaseMeta struct {
 	Host     string `yaml:"host"`
 	User     string `yaml:"user"`
-	Password string `yaml:"password"`
+	Password string `yaml:"password" redact:"true"`
 	Database string `yaml:"database"`
 	Minpool  int    `yaml:"minpool"`
 	Maxpool  int    `yaml:"maxpool"`
 }
 
-func LoadConfig() (config AppConfig) {
-	b, err := os.ReadFile("setting/setting.yaml")
-	if err != nil {
-		panic(err)
-	}
-
-	if err := yaml.Unmarshal(b, &config); err != nil {
-		panic(err)
-	}
-
-	// load token to environment
-	b, err = os.ReadFile(".telegram-token")
-	if err != nil {
-		panic(err)
-	}
-
-	r := strings.NewReplacer("\n", "", "\r", "")
-	token := r.Replace(string(b))
-	if err = os.Setenv(config.Telegram.TokenEnv, token); err != nil {
-		panic(err)
-	}
-
-	if config.Grpc.Mode != "production" {
-		debug.DebugStruct(config)
-	}
-
-	return config
+type historyMeta struct {
+	// messages older than this are purged, 0 keeps them forever
+	RetentionDays int `yaml:"retention_days"`
+
+	// how often the bot purges expired messages
+	PurgeInterval Duration `yaml:"purge_interval"`
+}
+
+type i18nMeta struct {
+	// directory containing `<locale>.yaml` message catalogs
+	Dir string `yaml:"dir"`
+
+	// directory containing `<locale>/<name>.<md|html|txt>` message templates
+	TemplateDir string `yaml:"template_dir"`
+
+	// used when user's language is not available
+	DefaultLocale string `yaml:"default_locale"`
+}
+
+type metricsMeta struct {
+	// address to serve `/metrics` of each binary on, empty disables it
+	BotListener  string `yaml:"bot_listener"`
+	GrpcListener string `yaml:"grpc_listener"`
+}
+
+type tracingMeta struct {
+	// none, stdout (local debugging) or otlp
+	Exporter string `yaml:"exporter"`
+
+	// OTLP gRPC collector address, only for otlp exporter
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"`
+
+	// fraction of traces recorded, from 0 to 1
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type logMeta struct {
+	// trace, debug, info, warn or error
+	Level string `yaml:"level"`
+
+	// json or console
+	Format string `yaml:"format"`
+
+	// also write to stdout, logfile of each binary is always written
+	Stdout bool `yaml:"stdout"`
+
+	// mask usernames & display names in logs & debug output
+	MaskNames bool `yaml:"mask_names"`
+
+	// mask message text, captions & bios in logs & debug output
+	MaskText bool `yaml:"mask_text"`
 }
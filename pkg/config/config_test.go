@@ -1,23 +1,158 @@
 package config
 
 import (
-	"fmt"
+	"flag"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// $DIR is replaced by the test's temp dir
+const testSetting = `
+grpc:
+  listener: 127.0.0.1:13467
+  timeout: 60
+  mode: production
+  logfile: log/zerolog.log
+telegram:
+  token:
+    source: file
+    ref: $DIR/token
+  bot:
+    logfile: log/bot.log
+    timeout: 30s
+redis:
+  host: 127.0.0.1
+  port: 6379
+  cache:
+    ttl: 10m
+db:
+  host: 127.0.0.1:3306
+  user: bidoof
+  password: from_file
+  database: bidoof
+  maxpool: 10
+i18n:
+  dir: $DIR/locales
+  template_dir: $DIR/templates
+  default_locale: en
+tracing:
+  sample_ratio: 0.5
+`
+
+// write setting, token & default locale catalog to temp dir, returns options
+// reading them
+func testOptions(t *testing.T, setting string) Options {
+	dir := t.TempDir()
+
+	opts := Options{
+		Path:      filepath.Join(dir, "setting.yaml"),
+		LookupEnv: func(string) (string, bool) { return "", false },
+	}
+	setting = strings.ReplaceAll(setting, "$DIR", dir)
+	require.NoError(t, os.WriteFile(opts.Path, []byte(setting), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("123:abc\n"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "locales"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "locales", "en.yaml"), nil, 0o600))
+
+	return opts
+}
+
 func TestLoadConfig(t *testing.T) {
-	defer func() {
-		if err := recover(); err != nil {
-			t.Fatal(err)
-		}
-	}()
+	cfg, err := Load(testOptions(t, testSetting))
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:13467", cfg.Grpc.Listener)
+	assert.Equal(t, 10, cfg.DB.Maxpool)
+	assert.Equal(t, 0.5, cfg.Tracing.SampleRatio)
+	assert.Equal(t, time.Minute, cfg.Grpc.Timeout.Duration())
+	assert.Equal(t, 30*time.Second, cfg.Telegram.Bot.Timeout.Duration())
+	assert.Equal(t, 10*time.Minute, cfg.Redis.Cache.TTL.Duration())
+	assert.Equal(t, "123:abc", cfg.Telegram.Token.Value.Reveal())
+}
+
+func TestLoadLayers(t *testing.T) {
+	opts := testOptions(t, testSetting)
+
+	env := map[string]string{
+		"BIDOOF_DB_PASSWORD":     "from_env",
+		"BIDOOF_DB_MAXPOOL":      "20",
+		"BIDOOF_GRPC_MODE":       "production",
+		"BIDOOF_TELEGRAM_ADMINS": "11, 22",
+	}
+	opts.LookupEnv = func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	opts.Overrides = map[string]string{
+		"db.maxpool":           "30",
+		"metrics.bot_listener": "",
+		"log.stdout":           "true",
+	}
 
-	cfg := LoadConfig()
+	cfg, err := Load(opts)
+	require.NoError(t, err)
 
-	if assert.NotEmpty(t, os.Getenv(cfg.Telegram.TokenEnv)) {
-		fmt.Printf("os.Getenv(cfg.Telegram.TokenEnv): %v\n", os.Getenv(cfg.Telegram.TokenEnv))
+	// env overrides file, overrides override env
+	assert.Equal(t, "from_env", cfg.DB.Password)
+	assert.Equal(t, 30, cfg.DB.Maxpool)
+	assert.Equal(t, "", cfg.Metrics.BotListener)
+	assert.True(t, cfg.Log.Stdout)
+	assert.Equal(t, []int64{11, 22}, cfg.Telegram.Admins)
+
+	// untouched
+	assert.Equal(t, "127.0.0.1:3306", cfg.DB.Host)
+}
+
+func TestLoadError(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Modify func(*Options)
+	}{
+		{"missing file", func(o *Options) { o.Path += ".missing" }},
+		{"missing token", func(o *Options) { o.Overrides = map[string]string{"telegram.token.ref": "missing"} }},
+		{"unknown override", func(o *Options) { o.Overrides = map[string]string{"db.port": "3306"} }},
+		{"invalid override", func(o *Options) { o.Overrides = map[string]string{"db.maxpool": "many"} }},
+		{"invalid duration", func(o *Options) { o.Overrides = map[string]string{"grpc.timeout": "soon"} }},
+		{"invalid config", func(o *Options) { o.Overrides = map[string]string{"grpc.mode": "staging"} }},
+		{"invalid env", func(o *Options) {
+			o.LookupEnv = func(key string) (string, bool) { return "maybe", key == "BIDOOF_LOG_STDOUT" }
+		}},
 	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			opts := testOptions(t, testSetting)
+			tc.Modify(&opts)
+
+			_, err := Load(opts)
+			assert.Error(t, err)
+		})
+	}
+
+	opts := testOptions(t, "grpc: [")
+	_, err := Load(opts)
+	assert.Error(t, err)
+}
+
+func TestFlags(t *testing.T) {
+	opts := testOptions(t, testSetting)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagOpts := Flags(fs)
+
+	require.NoError(t, fs.Parse([]string{"--config", opts.Path, "--db.password", "from_flag", "--redis.cache.ttl=5s"}))
+
+	flagOpts.LookupEnv = opts.LookupEnv
+	cfg, err := Load(*flagOpts)
+	require.NoError(t, err)
+	assert.Equal(t, "from_flag", cfg.DB.Password)
+	assert.Equal(t, 5*time.Second, cfg.Redis.Cache.TTL.Duration())
+
+	assert.Error(t, fs.Parse([]string{"--db.port", "3306"}))
 }
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DEFAULT_PATH = "setting/setting.yaml"
+
+	// every field can be overridden by env var named after its key, e.g.
+	// `db.password` by BIDOOF_DB_PASSWORD
+	ENV_PREFIX = "BIDOOF_"
+)
+
+// Where Load reads configuration from. Layers are applied in order: YAML
+// file, environment, overrides. The result is validated afterwards.
+type Options struct {
+	// YAML file, DEFAULT_PATH if empty
+	Path string
+
+	// environment lookup, os.LookupEnv if nil
+	LookupEnv func(key string) (string, bool)
+
+	// field key (e.g. `db.password`) to value, usually from CLI flags
+	Overrides map[string]string
+}
+
+// Parse config from `opts` & read the Telegram token from its secret source
+func Load(opts Options) (config AppConfig, err error) {
+	if config, err = Parse(opts); err != nil {
+		return config, err
+	}
+
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	token := config.Telegram.Token
+	if config.Telegram.Token.Value, err = ReadSecret(token.Source, token.Ref, opts.LookupEnv); err != nil {
+		return config, fmt.Errorf("config: telegram.token: %w", err)
+	}
+
+	return config, nil
+}
+
+// Read & validate config from `opts` without touching secrets, the token
+// value is left empty. Used on reload, which keeps the token read at startup.
+func Parse(opts Options) (config AppConfig, err error) {
+	if len(opts.Path) == 0 {
+		opts.Path = DEFAULT_PATH
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	b, err := os.ReadFile(opts.Path)
+	if err != nil {
+		return config, fmt.Errorf("config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(b, &config); err != nil {
+		return config, fmt.Errorf("config: %s: %w", opts.Path, err)
+	}
+
+	fields := fieldsOf(&config)
+
+	for _, f := range fields {
+		if v, ok := opts.LookupEnv(f.env()); ok {
+			if err := f.set(v); err != nil {
+				return config, fmt.Errorf("config: %s: %w", f.env(), err)
+			}
+		}
+	}
+
+	for key, v := range opts.Overrides {
+		f, ok := fields[key]
+		if !ok {
+			return config, fmt.Errorf("config: unknown key %q", key)
+		}
+		if err := f.set(v); err != nil {
+			return config, fmt.Errorf("config: %s: %w", key, err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// Register `--config` & one flag per config field (e.g.
+// `--db.password`) on `fs`. The returned options are filled once fs is
+// parsed, with the flags set as overrides.
+func Flags(fs *flag.FlagSet) *Options {
+	opts := &Options{Overrides: make(map[string]string)}
+	fs.StringVar(&opts.Path, "config", DEFAULT_PATH, "path to YAML config file")
+
+	var config AppConfig
+	fields := fieldsOf(&config)
+
+	for _, key := range sortedKeys(fields) {
+		key := key
+		usage := fmt.Sprintf("override %s (%s), also settable with %s", key, fields[key].typeName(), fields[key].env())
+		fs.Func(key, usage, func(v string) error {
+			opts.Overrides[key] = v
+			return nil
+		})
+	}
+
+	return opts
+}
+
+// settable leaf of AppConfig
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+func (f field) env() string {
+	return ENV_PREFIX + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+func (f field) set(s string) error {
+	if f.isDuration() {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(s)
+
+	case reflect.Int:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(v))
+
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetFloat(v)
+
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(v)
+
+	// comma separated, empty string clears the list
+	case reflect.Slice:
+		if f.value.Type().Elem().Kind() != reflect.Int64 {
+			return fmt.Errorf("unsupported type %s", f.value.Type())
+		}
+
+		list := []int64{}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); len(item) == 0 {
+				continue
+			}
+			v, err := strconv.ParseInt(item, 10, 64)
+			if err != nil {
+				return err
+			}
+			list = append(list, v)
+		}
+		f.value.Set(reflect.ValueOf(list))
+
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return nil
+}
+
+func (f field) isDuration() bool {
+	return f.value.Type() == reflect.TypeOf(Duration(0))
+}
+
+// type shown in flag usage
+func (f field) typeName() string {
+	if f.isDuration() {
+		return "duration"
+	}
+	if f.value.Kind() == reflect.Slice {
+		return "comma separated " + f.value.Type().Elem().Kind().String() + " list"
+	}
+
+	return f.value.Kind().String()
+}
+
+// leaf fields of `config` by key, made of the yaml names of the path to them
+func fieldsOf(config *AppConfig) map[string]field {
+	fields := make(map[string]field)
+	collectFields(reflect.ValueOf(config).Elem(), "", fields)
+
+	return fields
+}
+
+func collectFields(v reflect.Value, prefix string, fields map[string]field) {
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+		if v.Field(i).Kind() == reflect.Struct {
+			collectFields(v.Field(i), key+".", fields)
+			continue
+		}
+
+		fields[key] = field{key, v.Field(i)}
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// Keys applied on reload, entries ending with "." cover the whole section.
+// Changes to anything else are reported & need a restart.
+var RELOADABLE = []string{
+	"grpc.timeout",
+	"telegram.admins",
+	"telegram.allowlist_only",
+	"telegram.bot.timeout",
+	"redis.cache.",
+	"i18n.",
+	"log.level",
+	"log.mask_names",
+	"log.mask_text",
+}
+
+// file changes are usually several events (truncate, write, rename), wait for
+// them to settle before reloading
+const RELOAD_DEBOUNCE = 500 * time.Millisecond
+
+// Config shared by running services. Reload swaps it atomically, so a reader
+// sees either the old or the new config, never a mix of both.
+type Store struct {
+	opts    Options
+	current atomic.Pointer[AppConfig]
+
+	// serializes reloads
+	mu    sync.Mutex
+	hooks []ReloadHook
+
+	// keys this process doesn't use, see Ignore
+	ignored []string
+}
+
+// Prepare everything a reload needs from the config about to be applied,
+// e.g. parse message catalogs, without changing anything yet. The returned
+// `apply` makes the change & must not fail, it only runs once every hook
+// prepared successfully.
+type ReloadHook func(next *AppConfig) (apply func(), err error)
+
+// Keys that differ between the running & the reloaded config
+type ReloadResult struct {
+	Applied []string
+
+	// not reloadable, kept at the running value until restart
+	RestartRequired []string
+
+	// not used by this process, see Ignore
+	Ignored []string
+}
+
+// `opts` are reused on every reload, so the same file, env & flags apply
+func NewStore(cfg AppConfig, opts Options) *Store {
+	s := &Store{opts: opts}
+	s.current.Store(&cfg)
+
+	return s
+}
+
+func (s *Store) Get() *AppConfig {
+	return s.current.Load()
+}
+
+// Keys this process doesn't use, in the same form as RELOADABLE. Their changes
+// are reported as ignored rather than applied or needing a restart.
+func (s *Store) Ignore(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ignored = append(s.ignored, keys...)
+}
+
+// Register `hook` to run on every reload with the config about to be applied.
+// A failing hook aborts the reload, no hook is applied & the running config is
+// kept.
+func (s *Store) OnReload(hook ReloadHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, hook)
+}
+
+// Load config again & apply its reloadable changes. On error (invalid config,
+// failing hook) nothing is applied.
+func (s *Store) Reload() (*ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// secrets aren't read again, the running token is kept (changing its
+	// source needs a restart)
+	loaded, err := Parse(s.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// start from the running config & copy over only what can be reloaded
+	running := s.Get()
+	next := *running
+	res := new(ReloadResult)
+
+	nextFields, loadedFields := fieldsOf(&next), fieldsOf(&loaded)
+	for _, key := range sortedKeys(loadedFields) {
+		was, now := nextFields[key].value, loadedFields[key].value
+		if reflect.DeepEqual(was.Interface(), now.Interface()) {
+			continue
+		}
+
+		switch {
+		case matchKey(s.ignored, key):
+			res.Ignored = append(res.Ignored, key)
+
+		case matchKey(RELOADABLE, key):
+			was.Set(now)
+			res.Applied = append(res.Applied, key)
+
+		default:
+			res.RestartRequired = append(res.RestartRequired, key)
+		}
+	}
+
+	applies := make([]func(), 0, len(s.hooks))
+	for _, hook := range s.hooks {
+		apply, err := hook(&next)
+		if err != nil {
+			return nil, err
+		}
+		applies = append(applies, apply)
+	}
+
+	// side effects only once every hook is ready
+	for _, apply := range applies {
+		apply()
+	}
+
+	s.current.Store(&next)
+
+	return res, nil
+}
+
+// Reload on SIGHUP & whenever files under `paths` change, until ctx is done.
+// Directories are watched with their subdirectories.
+func (s *Store) Watch(ctx context.Context, paths ...string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := watchTree(watcher, path); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(hup)
+
+		debounce := time.NewTimer(RELOAD_DEBOUNCE)
+		debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-hup:
+				s.reloadAndLog(ctx, "sighup")
+
+			case event := <-watcher.Events:
+				log.Ctx(ctx).Debug().Str("file", event.Name).Str("op", event.Op.String()).Msg("config.watch")
+				debounce.Reset(RELOAD_DEBOUNCE)
+
+			case err := <-watcher.Errors:
+				log.Ctx(ctx).Error().Err(err).Msg("config.watch")
+
+			case <-debounce.C:
+				s.reloadAndLog(ctx, "file")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *Store) reloadAndLog(ctx context.Context, trigger string) {
+	res, err := s.Reload()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("trigger", trigger).Msg("config.reload")
+		return
+	}
+
+	log.Ctx(ctx).Info().Str("trigger", trigger).Strs("applied", res.Applied).Strs("ignored", res.Ignored).Msg("config.reload")
+	if len(res.RestartRequired) != 0 {
+		log.Ctx(ctx).Warn().Strs("keys", res.RestartRequired).Msg("config.reload.restart_required")
+	}
+}
+
+// whether `key` is one of `keys`, entries ending with "." cover the whole
+// section
+func matchKey(keys []string, key string) bool {
+	for _, r := range keys {
+		if key == r || (strings.HasSuffix(r, ".") && strings.HasPrefix(key, r)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// editors replace files instead of writing them, so directories are watched
+// rather than files
+func watchTree(watcher *fsnotify.Watcher, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return watcher.Add(filepath.Dir(path))
+	}
+
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+
+		return watcher.Add(p)
+	})
+}
+
+func sortedKeys(fields map[string]field) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewrite setting file of `opts`, replacing `old` by `new`
+func editSetting(t *testing.T, opts Options, old, new string) {
+	b, err := os.ReadFile(opts.Path)
+	require.NoError(t, err)
+	require.Contains(t, string(b), old)
+	require.NoError(t, os.WriteFile(opts.Path, []byte(strings.ReplaceAll(string(b), old, new)), 0o600))
+}
+
+func TestReload(t *testing.T) {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+	store := NewStore(cfg, opts)
+
+	editSetting(t, opts, "ttl: 10m", "ttl: 5m")
+	editSetting(t, opts, "maxpool: 10", "maxpool: 20")
+
+	res, err := store.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redis.cache.ttl"}, res.Applied)
+	assert.Equal(t, []string{"db.maxpool"}, res.RestartRequired)
+
+	// non reloadable key keeps running value
+	assert.Equal(t, 5*time.Minute, store.Get().Redis.Cache.TTL.Duration())
+	assert.Equal(t, 10, store.Get().DB.Maxpool)
+}
+
+func TestReloadError(t *testing.T) {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+	store := NewStore(cfg, opts)
+	running := store.Get()
+
+	// failing hook keeps running config, and hooks that were ready are not
+	// applied either
+	applied := false
+	store.OnReload(func(*AppConfig) (func(), error) {
+		return func() { applied = true }, nil
+	})
+	hookErr := errors.New("catalog broken")
+	store.OnReload(func(*AppConfig) (func(), error) { return nil, hookErr })
+	editSetting(t, opts, "ttl: 10m", "ttl: 5m")
+
+	_, err = store.Reload()
+	assert.ErrorIs(t, err, hookErr)
+	assert.Same(t, running, store.Get())
+	assert.False(t, applied)
+
+	// so does invalid config
+	editSetting(t, opts, "timeout: 30s", "timeout: 0s")
+
+	_, err = store.Reload()
+	var vErr *ValidationError
+	assert.ErrorAs(t, err, &vErr)
+	assert.Same(t, running, store.Get())
+}
+
+func TestWatch(t *testing.T) {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+	store := NewStore(cfg, opts)
+
+	reloaded := make(chan *AppConfig, 1)
+	store.OnReload(func(c *AppConfig) (func(), error) {
+		return func() {
+			select {
+			case reloaded <- c:
+			default:
+			}
+		}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, store.Watch(ctx, opts.Path))
+
+	editSetting(t, opts, "ttl: 10m", "ttl: 5m")
+
+	select {
+	case c := <-reloaded:
+		assert.Equal(t, 5*time.Minute, c.Redis.Cache.TTL.Duration())
+	case <-time.After(5 * time.Second):
+		t.Fatal("config not reloaded on file change")
+	}
+}
+
+func TestReloadKeepsToken(t *testing.T) {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+	store := NewStore(cfg, opts)
+
+	// secret source is not read again
+	require.NoError(t, os.Remove(filepath.Join(filepath.Dir(opts.Path), "token")))
+	editSetting(t, opts, "ttl: 10m", "ttl: 5m")
+
+	_, err = store.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", store.Get().Telegram.Token.Value.Reveal())
+}
+
+func TestReloadIgnored(t *testing.T) {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+	store := NewStore(cfg, opts)
+	store.Ignore("redis.cache.")
+
+	editSetting(t, opts, "ttl: 10m", "ttl: 5m")
+
+	res, err := store.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, res.Applied)
+	assert.Equal(t, []string{"redis.cache.ttl"}, res.Ignored)
+	assert.Equal(t, 10*time.Minute, store.Get().Redis.Cache.TTL.Duration())
+}
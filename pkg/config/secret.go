@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+)
+
+// telegram.token.source
+const (
+	SECRET_FILE    = "file"
+	SECRET_ENV     = "env"
+	SECRET_COMMAND = "command"
+	SECRET_MOUNT   = "mount"
+)
+
+const (
+	// where Docker mounts secrets, `mount` refs that are not absolute paths are
+	// looked up here
+	SECRET_MOUNT_DIR = "/run/secrets"
+
+	SECRET_COMMAND_TIMEOUT = 10 * time.Second
+)
+
+// Value that must not end up in logs or debug output. Printing or marshaling
+// it gives debug.REDACTED, use Reveal to get the actual value.
+type Secret string
+
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	return debug.REDACTED
+}
+
+func (s Secret) GoString() string {
+	return debug.REDACTED
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(debug.REDACTED), nil
+}
+
+func (s Secret) MarshalYAML() (any, error) {
+	return debug.REDACTED, nil
+}
+
+// Read secret from `source`, `ref` meaning depends on it:
+//   - file: path of file containing the secret
+//   - env: name of env var containing the secret
+//   - command: command printing the secret to stdout, run without shell
+//   - mount: Docker secret name or path of K8s secret volume file
+//
+// Trailing newline is trimmed. The secret is only returned, never put in the
+// environment.
+func ReadSecret(source, ref string, lookupEnv func(string) (string, bool)) (Secret, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	switch source {
+	case SECRET_FILE:
+		b, err = os.ReadFile(ref)
+
+	case SECRET_ENV:
+		v, ok := lookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("env var %s not set", ref)
+		}
+		b = []byte(v)
+
+	case SECRET_COMMAND:
+		b, err = runSecretCommand(ref)
+
+	case SECRET_MOUNT:
+		if !filepath.IsAbs(ref) {
+			ref = filepath.Join(SECRET_MOUNT_DIR, ref)
+		}
+		b, err = os.ReadFile(ref)
+
+	default:
+		return "", fmt.Errorf("unknown secret source %q", source)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimRight(string(b), "\r\n")
+	if len(secret) == 0 {
+		return "", errors.New("empty secret")
+	}
+
+	return Secret(secret), nil
+}
+
+func runSecretCommand(command string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SECRET_COMMAND_TIMEOUT)
+	defer cancel()
+
+	// stderr is left out of the error, it may echo the secret
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return out, nil
+}
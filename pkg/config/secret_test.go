@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+)
+
+func TestReadSecret(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(file, []byte("123:abc\r\n"), 0o600))
+
+	lookupEnv := func(key string) (string, bool) {
+		return "123:abc", key == "TELEGRAM_TOKEN"
+	}
+
+	testCases := []struct {
+		Source string
+		Ref    string
+		Valid  bool
+	}{
+		{SECRET_FILE, file, true},
+		{SECRET_FILE, file + ".missing", false},
+		{SECRET_ENV, "TELEGRAM_TOKEN", true},
+		{SECRET_ENV, "OTHER_TOKEN", false},
+		{SECRET_COMMAND, "echo 123:abc", true},
+		{SECRET_COMMAND, "false", false},
+		{SECRET_MOUNT, file, true},
+		{"vault", "bidoof/telegram", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Source+" "+tc.Ref, func(t *testing.T) {
+			secret, err := ReadSecret(tc.Source, tc.Ref, lookupEnv)
+			if !tc.Valid {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, "123:abc", secret.Reveal())
+			}
+		})
+	}
+}
+
+func TestSecretRedacted(t *testing.T) {
+	cfg := validConfig(t)
+	require.Equal(t, "123:abc", cfg.Telegram.Token.Value.Reveal())
+
+	b, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "123:abc")
+	assert.Contains(t, string(b), debug.REDACTED)
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		assert.NotContains(t, fmt.Sprintf(format, cfg), "123:abc", format)
+	}
+
+	// never put in environment
+	for _, env := range os.Environ() {
+		assert.NotContains(t, env, "123:abc")
+	}
+}
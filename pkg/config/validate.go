@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// grpc.mode
+const (
+	MODE_DEVELOPMENT = "development"
+	MODE_PRODUCTION  = "production"
+)
+
+// All problems found in a config, so they can be fixed in one go instead of
+// one restart per mistake
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "config: invalid:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(key, format string, args ...any) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(key, value string) {
+	if len(value) == 0 {
+		v.addf(key, "required")
+	}
+}
+
+// host:port, empty is only allowed when `optional`
+func (v *validator) listener(key, value string, optional bool) {
+	if len(value) == 0 {
+		if !optional {
+			v.addf(key, "required")
+		}
+		return
+	}
+
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		v.addf(key, "must be host:port, got %q", value)
+	}
+}
+
+func (v *validator) positive(key string, value Duration) {
+	if value <= 0 {
+		v.addf(key, "must be greater than 0, got %s", value)
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.addf(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// Check required fields, ranges & enums. Returns *ValidationError listing
+// every problem, nil if config is usable.
+func (c *AppConfig) Validate() error {
+	v := new(validator)
+
+	v.listener("grpc.listener", c.Grpc.Listener, false)
+	v.positive("grpc.timeout", c.Grpc.Timeout)
+	v.oneOf("grpc.mode", c.Grpc.Mode, MODE_DEVELOPMENT, MODE_PRODUCTION)
+	v.required("grpc.logfile", c.Grpc.Logfile)
+
+	v.oneOf("telegram.token.source", c.Telegram.Token.Source, SECRET_FILE, SECRET_ENV, SECRET_COMMAND, SECRET_MOUNT)
+	v.required("telegram.token.ref", c.Telegram.Token.Ref)
+	v.required("telegram.bot.logfile", c.Telegram.Bot.Logfile)
+	v.positive("telegram.bot.timeout", c.Telegram.Bot.Timeout)
+	for _, id := range c.Telegram.Admins {
+		// only private chats can be admins, their IDs are user IDs
+		if id <= 0 {
+			v.addf("telegram.admins", "must be user IDs, got %d", id)
+		}
+	}
+
+	v.required("redis.host", c.Redis.Host)
+	v.required("redis.port", c.Redis.Port)
+	if c.Redis.Cache.TTL < 0 {
+		v.addf("redis.cache.ttl", "must not be negative, got %s", c.Redis.Cache.TTL)
+	}
+	if c.Redis.Cache.NegativeTTL < 0 {
+		v.addf("redis.cache.negative_ttl", "must not be negative, got %s", c.Redis.Cache.NegativeTTL)
+	}
+
+	v.listener("db.host", c.DB.Host, false)
+	v.required("db.user", c.DB.User)
+	v.required("db.database", c.DB.Database)
+	if c.DB.Maxpool < 1 {
+		v.addf("db.maxpool", "must be at least 1, got %d", c.DB.Maxpool)
+	}
+	if c.DB.Minpool < 0 || c.DB.Minpool > c.DB.Maxpool {
+		v.addf("db.minpool", "must be between 0 and db.maxpool (%d), got %d", c.DB.Maxpool, c.DB.Minpool)
+	}
+
+	if c.History.RetentionDays < 0 {
+		v.addf("history.retention_days", "must not be negative, got %d", c.History.RetentionDays)
+	}
+	if c.History.RetentionDays > 0 {
+		v.positive("history.purge_interval", c.History.PurgeInterval)
+	}
+
+	v.required("i18n.dir", c.I18n.Dir)
+	v.required("i18n.template_dir", c.I18n.TemplateDir)
+	v.required("i18n.default_locale", c.I18n.DefaultLocale)
+	if len(c.I18n.Dir) != 0 && len(c.I18n.DefaultLocale) != 0 {
+		catalog := filepath.Join(c.I18n.Dir, c.I18n.DefaultLocale+".yaml")
+		if _, err := os.Stat(catalog); err != nil {
+			v.addf("i18n.default_locale", "messages of default locale not found: %s", catalog)
+		}
+	}
+
+	v.listener("metrics.bot_listener", c.Metrics.BotListener, true)
+	v.listener("metrics.grpc_listener", c.Metrics.GrpcListener, true)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "", "none", "stdout", "otlp")
+	if c.Tracing.Exporter == "otlp" {
+		v.listener("tracing.endpoint", c.Tracing.Endpoint, false)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	if _, err := zerolog.ParseLevel(c.Log.Level); err != nil {
+		v.addf("log.level", "must be one of trace, debug, info, warn, error, got %q", c.Log.Level)
+	}
+	v.oneOf("log.format", c.Log.Format, "", "json", "console")
+
+	if len(v.problems) != 0 {
+		return &ValidationError{v.problems}
+	}
+
+	return nil
+}
+
+// Whether `args` left after flag parsing is the `config check` subcommand
+func IsCheckCommand(args []string) bool {
+	return len(args) == 2 && args[0] == "config" && args[1] == "check"
+}
+
+// Run `config check`: load config from `opts`, report to `w` & return the
+// process exit code
+func Check(w io.Writer, opts Options) int {
+	if _, err := Load(opts); err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+
+	fmt.Fprintln(w, "config: OK")
+
+	return 0
+}
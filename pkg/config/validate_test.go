@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func validConfig(t *testing.T) AppConfig {
+	opts := testOptions(t, testSetting)
+	cfg, err := Load(opts)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	cfg := validConfig(t)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Grpc.Listener = "13467"
+	cfg.Grpc.Mode = "staging"
+	cfg.Telegram.Bot.Timeout = 0
+	cfg.DB.Minpool = 20
+	cfg.I18n.DefaultLocale = "fr"
+	cfg.Tracing.Exporter = "otlp"
+	cfg.Tracing.SampleRatio = 2
+	cfg.Log.Level = "verbose"
+
+	var vErr *ValidationError
+	require.True(t, errors.As(cfg.Validate(), &vErr))
+
+	// every problem is reported, not only the first one
+	assert.Len(t, vErr.Problems, 8)
+	for _, key := range []string{
+		"grpc.listener", "grpc.mode", "telegram.bot.timeout", "db.minpool",
+		"i18n.default_locale", "tracing.endpoint", "tracing.sample_ratio", "log.level",
+	} {
+		assert.Contains(t, vErr.Error(), key+": ")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected time.Duration
+		Valid    bool
+	}{
+		{"30s", 30 * time.Second, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"60", time.Minute, true},
+		{"soon", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			var v struct {
+				Timeout Duration `yaml:"timeout"`
+			}
+
+			err := yaml.Unmarshal([]byte("timeout: "+tc.Input), &v)
+			if !tc.Valid {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.Expected, v.Timeout.Duration())
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	opts := testOptions(t, testSetting)
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, Check(&out, opts))
+	assert.Contains(t, out.String(), "OK")
+
+	require.NoError(t, os.Remove(filepath.Join(filepath.Dir(opts.Path), "locales", "en.yaml")))
+
+	out.Reset()
+	assert.Equal(t, 1, Check(&out, opts))
+	assert.Contains(t, out.String(), "i18n.default_locale")
+
+	assert.True(t, IsCheckCommand([]string{"config", "check"}))
+	assert.False(t, IsCheckCommand([]string{"config"}))
+}
@@ -0,0 +1,88 @@
+package datasource
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Add chat to the allowlist, allowing it again only updates `allowed_by`
+func (ds *DataSource) AllowChat(ctx context.Context, allow *ChatAllow) (err error) {
+	ctx, done := startQuery(ctx, "AllowChat")
+	defer func() { done(err) }()
+
+	q := `
+        INSERT INTO telegram_chat_allow
+            (chat_id, allowed_by, created_at)
+        VALUES
+            (:chat_id, :allowed_by, UTC_TIMESTAMP())
+        ON DUPLICATE KEY UPDATE
+            allowed_by = VALUES(allowed_by)
+    `
+
+	if _, err = ds.DB.NamedExecContext(ctx, q, allow); err != nil {
+		return err
+	}
+	ds.invalidate(ctx, chatAllowCacheKey(allow.ChatID))
+
+	return nil
+}
+
+// Remove chat from the allowlist, returns false if it wasn't in it
+func (ds *DataSource) DisallowChat(ctx context.Context, chatId int64) (removed bool, err error) {
+	ctx, done := startQuery(ctx, "DisallowChat")
+	defer func() { done(err) }()
+
+	q := `
+        DELETE FROM
+            telegram_chat_allow
+        WHERE
+            chat_id = ?
+    `
+
+	res, err := ds.DB.ExecContext(ctx, q, chatId)
+	if err != nil {
+		return false, err
+	}
+	ds.invalidate(ctx, chatAllowCacheKey(chatId))
+
+	n, err := res.RowsAffected()
+
+	return n != 0, err
+}
+
+// Whether chat is in the allowlist, cached
+func (ds *DataSource) IsChatAllowed(ctx context.Context, chatId int64) (allowed bool, err error) {
+	if ds.getCached(ctx, chatAllowCacheKey(chatId), &allowed) {
+		return allowed, nil
+	}
+
+	if allowed, err = ds.isChatAllowedFromDB(ctx, chatId); err == nil {
+		ds.setCached(ctx, chatAllowCacheKey(chatId), allowed)
+	}
+
+	return allowed, err
+}
+
+func (ds *DataSource) isChatAllowedFromDB(ctx context.Context, chatId int64) (allowed bool, err error) {
+	ctx, done := startQuery(ctx, "IsChatAllowed")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            1
+        FROM
+            telegram_chat_allow
+        WHERE
+            chat_id = ?
+    `
+
+	var one int
+	switch err = ds.DB.GetContext(ctx, &one, q, chatId); {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
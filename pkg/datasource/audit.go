@@ -0,0 +1,93 @@
+package datasource
+
+import (
+	"context"
+	"time"
+)
+
+// filter for audit event queries, zero values are ignored
+type AuditEventFilter struct {
+	Caller string
+	Method string
+	Code   string
+	From   time.Time
+	To     time.Time
+}
+
+func (ds *DataSource) InsertAuditEvent(ctx context.Context, event *AuditEvent) (err error) {
+	ctx, done := startQuery(ctx, "InsertAuditEvent")
+	defer func() { done(err) }()
+
+	q := `
+        INSERT INTO telegram_audit_event
+            (request_id, caller, peer, method, params, code, error, created_at)
+        VALUES
+            (:request_id, :caller, :peer, :method, :params, :code, :error, UTC_TIMESTAMP())
+    `
+
+	_, err = ds.DB.NamedExecContext(ctx, q, event)
+
+	return err
+}
+
+// Get at most `limit` audit events with id less than `beforeId`, newest
+// first. Pass 0 as `beforeId` to get the first page.
+func (ds *DataSource) GetAuditEvents(ctx context.Context, filter AuditEventFilter, beforeId int64, limit int) (res []AuditEvent, err error) {
+	ctx, done := startQuery(ctx, "GetAuditEvents")
+	defer func() { done(err) }()
+
+	query := `
+        SELECT
+            id, request_id, caller, peer, method, params, code, error, created_at
+        FROM
+            telegram_audit_event
+        WHERE
+            1 = 1
+    `
+
+	where, args := filter.build()
+	if beforeId != 0 {
+		where = append(where, "id < ?")
+		args = append(args, beforeId)
+	}
+
+	for i := range where {
+		query += " AND " + where[i]
+	}
+
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	err = ds.DB.SelectContext(ctx, &res, query, args...)
+
+	return res, err
+}
+
+func (filter AuditEventFilter) build() (where []string, replacer []any) {
+	if len(filter.Caller) != 0 {
+		where = append(where, "caller = ?")
+		replacer = append(replacer, filter.Caller)
+	}
+
+	if len(filter.Method) != 0 {
+		where = append(where, "method = ?")
+		replacer = append(replacer, filter.Method)
+	}
+
+	if len(filter.Code) != 0 {
+		where = append(where, "code = ?")
+		replacer = append(replacer, filter.Code)
+	}
+
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= ?")
+		replacer = append(replacer, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < ?")
+		replacer = append(replacer, filter.To)
+	}
+
+	return where, replacer
+}
@@ -0,0 +1,135 @@
+package datasource
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Ban chat, banning it again replaces reason & `banned_by`
+func (ds *DataSource) BanChat(ctx context.Context, ban *ChatBan) (err error) {
+	ctx, done := startQuery(ctx, "BanChat")
+	defer func() { done(err) }()
+
+	q := `
+        INSERT INTO telegram_chat_ban
+            (chat_id, reason, banned_by, created_at)
+        VALUES
+            (:chat_id, :reason, :banned_by, UTC_TIMESTAMP())
+        ON DUPLICATE KEY UPDATE
+            reason = VALUES(reason),
+            banned_by = VALUES(banned_by)
+    `
+
+	if _, err = ds.DB.NamedExecContext(ctx, q, ban); err != nil {
+		return err
+	}
+	ds.invalidate(ctx, chatBanCacheKey(ban.ChatID))
+
+	return nil
+}
+
+// Lift ban of chat, returns false if it wasn't banned
+func (ds *DataSource) UnbanChat(ctx context.Context, chatId int64) (unbanned bool, err error) {
+	ctx, done := startQuery(ctx, "UnbanChat")
+	defer func() { done(err) }()
+
+	q := `
+        DELETE FROM
+            telegram_chat_ban
+        WHERE
+            chat_id = ?
+    `
+
+	res, err := ds.DB.ExecContext(ctx, q, chatId)
+	if err != nil {
+		return false, err
+	}
+	ds.invalidate(ctx, chatBanCacheKey(chatId))
+
+	n, err := res.RowsAffected()
+
+	return n != 0, err
+}
+
+// Get ban of chat, sql.ErrNoRows if it is not banned
+func (ds *DataSource) GetChatBan(ctx context.Context, chatId int64) (res *ChatBan, err error) {
+	ctx, done := startQuery(ctx, "GetChatBan")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            chat_id, reason, banned_by, created_at
+        FROM
+            telegram_chat_ban
+        WHERE
+            chat_id = ?
+    `
+
+	res = new(ChatBan)
+	err = ds.DB.GetContext(ctx, res, q, chatId)
+
+	return res, err
+}
+
+// Whether chat is banned, cached
+func (ds *DataSource) IsChatBanned(ctx context.Context, chatId int64) (banned bool, err error) {
+	if ds.getCached(ctx, chatBanCacheKey(chatId), &banned) {
+		return banned, nil
+	}
+
+	if banned, err = ds.isChatBannedFromDB(ctx, chatId); err == nil {
+		ds.setCached(ctx, chatBanCacheKey(chatId), banned)
+	}
+
+	return banned, err
+}
+
+func (ds *DataSource) isChatBannedFromDB(ctx context.Context, chatId int64) (bool, error) {
+	switch _, err := ds.GetChatBan(ctx, chatId); {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (ds *DataSource) CountChatBans(ctx context.Context) (count uint64, err error) {
+	ctx, done := startQuery(ctx, "CountChatBans")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            COUNT(*)
+        FROM
+            telegram_chat_ban
+    `
+
+	err = ds.DB.GetContext(ctx, &count, q)
+
+	return count, err
+}
+
+// Get at most `limit` bans with chat_id greater than `afterChatId`, ordered
+// by chat_id
+func (ds *DataSource) GetChatBans(ctx context.Context, afterChatId int64, limit int) (res []ChatBan, err error) {
+	ctx, done := startQuery(ctx, "GetChatBans")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            chat_id, reason, banned_by, created_at
+        FROM
+            telegram_chat_ban
+        WHERE
+            chat_id > ?
+        ORDER BY
+            chat_id
+        LIMIT ?
+    `
+
+	err = ds.DB.SelectContext(ctx, &res, q, afterChatId, limit)
+
+	return res, err
+}
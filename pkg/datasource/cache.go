@@ -0,0 +1,219 @@
+package datasource
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/go-redis/redis"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/metrics"
+)
+
+const (
+	PRIVATE_CHAT_CACHE_PREFIX = "private_chat:"
+
+	// checked by the bot on every update
+	CHAT_BAN_CACHE_PREFIX   = "chat_ban:"
+	CHAT_ALLOW_CACHE_PREFIX = "chat_allow:"
+	CHAT_ROLES_CACHE_PREFIX = "chat_roles:"
+
+	// stored for chat IDs that are not registered, so unregistered users
+	// spamming commands won't hit the database every time
+	NEGATIVE_CACHE_VALUE = "-"
+)
+
+// cache hit/miss counters, safe for concurrent use
+type cacheStats struct {
+	hits         atomic.Uint64
+	negativeHits atomic.Uint64
+	misses       atomic.Uint64
+	errors       atomic.Uint64
+}
+
+// snapshot of the cache counters
+type CacheStats struct {
+	Hits         uint64 `json:"hits"`
+	NegativeHits uint64 `json:"negative_hits"`
+	Misses       uint64 `json:"misses"`
+	Errors       uint64 `json:"errors"`
+}
+
+func (ds *DataSource) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:         ds.cacheStats.hits.Load(),
+		NegativeHits: ds.cacheStats.negativeHits.Load(),
+		Misses:       ds.cacheStats.misses.Load(),
+		Errors:       ds.cacheStats.errors.Load(),
+	}
+}
+
+// expose cache counters on the metrics endpoint
+func (ds *DataSource) WatchCacheMetrics() {
+	metrics.WatchCache(func() map[string]uint64 {
+		s := ds.CacheStats()
+		return map[string]uint64{
+			"hit":          s.Hits,
+			"negative_hit": s.NegativeHits,
+			"miss":         s.Misses,
+			"error":        s.Errors,
+		}
+	})
+}
+
+func privateChatCacheKey(chatId int64) string {
+	return PRIVATE_CHAT_CACHE_PREFIX + strconv.FormatInt(chatId, 10)
+}
+
+// look up private chat in cache. `hit` is false when the caller should fall
+// back to database, either because it's not cached or redis is unavailable.
+func (ds *DataSource) getCachedPrivateChat(ctx context.Context, chatId int64) (chat *PrivateChat, hit bool, err error) {
+	if ds.Redis == nil {
+		return nil, false, nil
+	}
+
+	val, err := ds.Redis.Get(privateChatCacheKey(chatId)).Result()
+	switch {
+	case err == redis.Nil:
+		ds.cacheStats.misses.Add(1)
+		return nil, false, nil
+
+	// redis is down, database is still the source of truth
+	case err != nil:
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chatId).Msg("cache.get")
+		return nil, false, nil
+
+	case val == NEGATIVE_CACHE_VALUE:
+		ds.cacheStats.negativeHits.Add(1)
+		return nil, true, sql.ErrNoRows
+	}
+
+	chat = new(PrivateChat)
+	if err := json.Unmarshal([]byte(val), chat); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chatId).Msg("cache.unmarshal")
+		return nil, false, nil
+	}
+
+	ds.cacheStats.hits.Add(1)
+	return chat, true, nil
+}
+
+func (ds *DataSource) cachePrivateChat(ctx context.Context, chat *PrivateChat) {
+	if ds.Redis == nil {
+		return
+	}
+
+	b, err := json.Marshal(chat)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chat.ChatID).Msg("cache.marshal")
+		return
+	}
+
+	ttl := ds.Config.Get().Redis.Cache.TTL.Duration()
+	if err := ds.Redis.Set(privateChatCacheKey(chat.ChatID), b, ttl).Err(); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chat.ChatID).Msg("cache.set")
+	}
+}
+
+func (ds *DataSource) cacheMissingPrivateChat(ctx context.Context, chatId int64) {
+	if ds.Redis == nil {
+		return
+	}
+
+	ttl := ds.Config.Get().Redis.Cache.NegativeTTL.Duration()
+	if err := ds.Redis.Set(privateChatCacheKey(chatId), NEGATIVE_CACHE_VALUE, ttl).Err(); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chatId).Msg("cache.set")
+	}
+}
+
+// must be called after every write to the private chat table, otherwise
+// lookups will serve stale data until TTL expires
+func (ds *DataSource) invalidatePrivateChat(ctx context.Context, chatId int64) {
+	if ds.Redis == nil {
+		return
+	}
+
+	if err := ds.Redis.Del(privateChatCacheKey(chatId)).Err(); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", chatId).Msg("cache.del")
+	}
+}
+
+func chatBanCacheKey(chatId int64) string {
+	return CHAT_BAN_CACHE_PREFIX + strconv.FormatInt(chatId, 10)
+}
+
+func chatAllowCacheKey(chatId int64) string {
+	return CHAT_ALLOW_CACHE_PREFIX + strconv.FormatInt(chatId, 10)
+}
+
+func chatRolesCacheKey(chatId int64) string {
+	return CHAT_ROLES_CACHE_PREFIX + strconv.FormatInt(chatId, 10)
+}
+
+// look up `key` in cache & decode it into `v`. Same as getCachedPrivateChat,
+// false when the caller should fall back to database.
+func (ds *DataSource) getCached(ctx context.Context, key string, v any) (hit bool) {
+	if ds.Redis == nil {
+		return false
+	}
+
+	val, err := ds.Redis.Get(key).Bytes()
+	switch {
+	case err == redis.Nil:
+		ds.cacheStats.misses.Add(1)
+		return false
+
+	case err != nil:
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("cache.get")
+		return false
+	}
+
+	if err := json.Unmarshal(val, v); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("cache.unmarshal")
+		return false
+	}
+
+	ds.cacheStats.hits.Add(1)
+	return true
+}
+
+// cache answer of a lookup, both positive & negative ones are kept for TTL
+// since every write invalidates them
+func (ds *DataSource) setCached(ctx context.Context, key string, v any) {
+	if ds.Redis == nil {
+		return
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("cache.marshal")
+		return
+	}
+
+	ttl := ds.Config.Get().Redis.Cache.TTL.Duration()
+	if err := ds.Redis.Set(key, b, ttl).Err(); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("cache.set")
+	}
+}
+
+// must be called after every write to the ban, allowlist & role tables
+func (ds *DataSource) invalidate(ctx context.Context, key string) {
+	if ds.Redis == nil {
+		return
+	}
+
+	if err := ds.Redis.Del(key).Err(); err != nil {
+		ds.cacheStats.errors.Add(1)
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("cache.del")
+	}
+}
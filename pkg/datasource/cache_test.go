@@ -0,0 +1,175 @@
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+)
+
+// datasource backed by miniredis only, any lookup reaching the database
+// panics on the nil DB
+func newCacheDataSource(t *testing.T) (*DataSource, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+
+	var cfg config.AppConfig
+	cfg.Redis.Cache.TTL = config.Duration(time.Minute)
+	cfg.Redis.Cache.NegativeTTL = config.Duration(10 * time.Second)
+
+	r := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { r.Close() })
+
+	return NewDataSource(config.NewStore(cfg, config.Options{}), nil, r), mr
+}
+
+func TestCacheHit(t *testing.T) {
+	ds, mr := newCacheDataSource(t)
+
+	ds.cachePrivateChat(context.Background(), &PrivateChat{ChatID: 1234, Username: "gabriel_s"})
+	assert.Equal(t, time.Minute, mr.TTL(privateChatCacheKey(1234)))
+
+	chat, err := ds.GetPrivateChat(context.Background(), 1234)
+	require.NoError(t, err)
+	assert.Equal(t, "gabriel_s", chat.Username)
+	assert.Equal(t, CacheStats{Hits: 1}, ds.CacheStats())
+}
+
+func TestCacheNegative(t *testing.T) {
+	ds, mr := newCacheDataSource(t)
+
+	ds.cacheMissingPrivateChat(context.Background(), 1234)
+	assert.Equal(t, 10*time.Second, mr.TTL(privateChatCacheKey(1234)))
+
+	_, err := ds.GetPrivateChat(context.Background(), 1234)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, CacheStats{NegativeHits: 1}, ds.CacheStats())
+
+	// expired, next lookup would go to database
+	mr.FastForward(11 * time.Second)
+	_, hit, _ := ds.getCachedPrivateChat(context.Background(), 1234)
+	assert.False(t, hit)
+	assert.Equal(t, uint64(1), ds.CacheStats().Misses)
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ds, mr := newCacheDataSource(t)
+
+	ds.cachePrivateChat(context.Background(), &PrivateChat{ChatID: 1234})
+	ds.invalidatePrivateChat(context.Background(), 1234)
+
+	assert.False(t, mr.Exists(privateChatCacheKey(1234)))
+	_, hit, _ := ds.getCachedPrivateChat(context.Background(), 1234)
+	assert.False(t, hit)
+}
+
+func TestCacheRedisDown(t *testing.T) {
+	ds, mr := newCacheDataSource(t)
+	mr.Close()
+
+	// warning is logged with the caller's logger
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).With().Str("request_id", "abc").Logger().WithContext(context.Background())
+
+	chat, hit, err := ds.getCachedPrivateChat(ctx, 1234)
+	assert.Nil(t, chat)
+	assert.False(t, hit)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{Errors: 1}, ds.CacheStats())
+	assert.Contains(t, buf.String(), `"request_id":"abc"`)
+	assert.Contains(t, buf.String(), `"message":"cache.get"`)
+}
+
+func TestCacheAccessLookups(t *testing.T) {
+	ds, mr := newCacheDataSource(t)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	ds.DB = sqlx.NewDb(db, "mysql")
+	ctx := context.Background()
+
+	// each lookup reaches the database once, then is served from cache
+	mock.ExpectQuery("telegram_chat_ban").WithArgs(1234).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("telegram_chat_allow").WithArgs(1234).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery("telegram_chat_role").WithArgs(1234).WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(ROLE_MODERATOR))
+
+	for i := 0; i < 2; i++ {
+		banned, err := ds.IsChatBanned(ctx, 1234)
+		require.NoError(t, err)
+		assert.False(t, banned)
+
+		allowed, err := ds.IsChatAllowed(ctx, 1234)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		moderator, err := ds.HasChatRole(ctx, 1234, ROLE_MODERATOR)
+		require.NoError(t, err)
+		assert.True(t, moderator)
+	}
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 3}, ds.CacheStats())
+	assert.Equal(t, time.Minute, mr.TTL(chatBanCacheKey(1234)))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// writes invalidate
+	mock.ExpectExec("telegram_chat_ban").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("telegram_chat_allow").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("telegram_chat_role").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, ds.BanChat(ctx, &ChatBan{ChatID: 1234}))
+	_, err = ds.DisallowChat(ctx, 1234)
+	require.NoError(t, err)
+	_, err = ds.RevokeChatRole(ctx, 1234, ROLE_MODERATOR)
+	require.NoError(t, err)
+
+	assert.False(t, mr.Exists(chatBanCacheKey(1234)))
+	assert.False(t, mr.Exists(chatAllowCacheKey(1234)))
+	assert.False(t, mr.Exists(chatRolesCacheKey(1234)))
+
+	// failed lookups are not cached
+	mock.ExpectQuery("telegram_chat_ban").WillReturnError(sql.ErrConnDone)
+	_, err = ds.IsChatBanned(ctx, 1234)
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.False(t, mr.Exists(chatBanCacheKey(1234)))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCacheTouchPrivateChat(t *testing.T) {
+	ds, _ := newCacheDataSource(t)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	ds.DB = sqlx.NewDb(db, "mysql")
+	ctx := context.Background()
+
+	// touched, fresh row is cached
+	ds.cachePrivateChat(ctx, &PrivateChat{ChatID: 1234, Username: "gabriel_s"})
+	mock.ExpectExec("deleted_at IS NULL").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, ds.TouchPrivateChat(ctx, &PrivateChat{ChatID: 1234, Username: "gabriel"}))
+
+	chat, hit, _ := ds.getCachedPrivateChat(ctx, 1234)
+	require.True(t, hit)
+	assert.Equal(t, "gabriel", chat.Username)
+	assert.NotNil(t, chat.LastSeenAt)
+
+	// stopped between the read & the update, nothing is written back
+	ds.cachePrivateChat(ctx, &PrivateChat{ChatID: 5678, Username: "gabriel_s"})
+	mock.ExpectExec("deleted_at IS NULL").WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, ds.TouchPrivateChat(ctx, &PrivateChat{ChatID: 5678, Username: "gabriel"}))
+
+	chat, hit, _ = ds.getCachedPrivateChat(ctx, 5678)
+	require.True(t, hit)
+	assert.Equal(t, "gabriel_s", chat.Username)
+	assert.Nil(t, chat.LastSeenAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,44 +1,97 @@
 package datasource
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/jmoiron/sqlx"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/metrics"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 )
 
 type DataSource struct {
-	Config *config.AppConfig
+	Config *config.Store
 	DB     *sqlx.DB
 	Redis  *redis.Client
+
+	cacheStats *cacheStats
 }
 
 type QueryFilter map[string]string
 
-func NewDataSource(c *config.AppConfig, db *sqlx.DB, r *redis.Client) *DataSource {
-	return &DataSource{c, db, r}
+// columns selected for PrivateChat
+const privateChatColumns = `
+            chat_id, username, name, bio, language_code, language, is_blocked,
+            created_at, updated_at, last_seen_at, started_at, stopped_at
+`
+
+func NewDataSource(c *config.Store, db *sqlx.DB, r *redis.Client) *DataSource {
+	return &DataSource{c, db, r, new(cacheStats)}
 }
 
 func NewQueryFilter() QueryFilter {
 	return make(QueryFilter)
 }
 
-func (ds *DataSource) InsertPrivateChatToDB(chat *PrivateChat) error {
+// Start span & latency metric of a database operation, the returned function
+// records the operation's result:
+//
+//	ctx, done := startQuery(ctx, "GetPrivateChat")
+//	defer func() { done(err) }()
+func startQuery(ctx context.Context, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "db."+operation, semconv.DBSystemMySQL, semconv.DBOperationKey.String(operation))
+
+	return ctx, func(err error) {
+		metrics.ObserveQuery(operation, start)
+
+		// not found is an answer, not a failure
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		tracing.End(span, err)
+	}
+}
+
+// Register private chat, or restore the profile of a previously stopped chat.
+// Records a "started" subscription event.
+func (ds *DataSource) InsertPrivateChatToDB(ctx context.Context, chat *PrivateChat) (err error) {
+	ctx, done := startQuery(ctx, "InsertPrivateChatToDB")
+	defer func() { done(err) }()
+
 	q := `
         INSERT INTO telegram_private_chat
-            (chat_id, name, username, bio)
+            (chat_id, name, username, bio, language_code, last_seen_at, started_at)
         VALUES
-            (:chat_id, :name, :username, :bio)
+            (:chat_id, :name, :username, :bio, :language_code, UTC_TIMESTAMP(), UTC_TIMESTAMP())
+        ON DUPLICATE KEY UPDATE
+            name = VALUES(name),
+            username = VALUES(username),
+            bio = COALESCE(NULLIF(VALUES(bio), ''), bio),
+            language_code = VALUES(language_code),
+            is_blocked = FALSE,
+            last_seen_at = UTC_TIMESTAMP(),
+            started_at = UTC_TIMESTAMP(),
+            deleted_at = NULL
     `
 
-	tx, err := ds.DB.Beginx()
+	tx, err := ds.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	if _, err = tx.NamedExec(q, chat); err != nil {
+	if _, err = tx.NamedExecContext(ctx, q, chat); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = insertSubscriptionEvent(ctx, tx, chat.ChatID, SUBSCRIPTION_STARTED); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -48,45 +101,256 @@ func (ds *DataSource) InsertPrivateChatToDB(chat *PrivateChat) error {
 		return err
 	}
 
+	ds.invalidatePrivateChat(ctx, chat.ChatID)
+
 	return nil
 }
 
-func (ds *DataSource) GetPrivateChat(chatId int64) (*PrivateChat, error) {
+// Get registered private chat, read-through cached in redis. Returns
+// sql.ErrNoRows if the chat is not registered or has been stopped.
+func (ds *DataSource) GetPrivateChat(ctx context.Context, chatId int64) (*PrivateChat, error) {
+	if chat, hit, err := ds.getCachedPrivateChat(ctx, chatId); hit {
+		return chat, err
+	}
+
+	res, err := ds.getPrivateChatFromDB(ctx, chatId)
+	switch {
+	case err == sql.ErrNoRows:
+		ds.cacheMissingPrivateChat(ctx, chatId)
+	case err == nil:
+		ds.cachePrivateChat(ctx, res)
+	}
+
+	return res, err
+}
+
+func (ds *DataSource) getPrivateChatFromDB(ctx context.Context, chatId int64) (res *PrivateChat, err error) {
+	ctx, done := startQuery(ctx, "GetPrivateChat")
+	defer func() { done(err) }()
+
 	var args []any
 	args = append(args, chatId)
 
 	q := `
-        SELECT
-            name
+        SELECT` + privateChatColumns + `
         FROM
             telegram_private_chat
         WHERE
             chat_id = ?
+            AND deleted_at IS NULL
     `
 
-	res := new(PrivateChat)
-	err := ds.DB.Get(res, q, args...)
+	res = new(PrivateChat)
+	err = ds.DB.GetContext(ctx, res, q, args...)
 
 	return res, err
 }
 
-func (ds *DataSource) DeletePrivateChat(chatId int64) error {
+// Refresh profile of a registered private chat from an incoming message.
+// Bio is only sent by Telegram on getChat, so an empty bio is not written.
+// Unregistered chats are left untouched. Profile columns & updated_at are
+// only written when the profile changed, and the fresh row is written back to
+// cache so lookups later in the same update don't miss. Chats stopped
+// meanwhile are neither updated nor cached again.
+func (ds *DataSource) TouchPrivateChat(ctx context.Context, chat *PrivateChat) (err error) {
+	current, err := ds.GetPrivateChat(ctx, chat.ChatID)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	ctx, done := startQuery(ctx, "TouchPrivateChat")
+	defer func() { done(err) }()
+
+	fresh, changed := touchedPrivateChat(current, chat, time.Now().UTC())
+
+	// setting updated_at to itself keeps ON UPDATE from bumping it
+	q := `
+        UPDATE
+            telegram_private_chat
+        SET
+            last_seen_at = :last_seen_at,
+            updated_at = updated_at
+        WHERE
+            chat_id = :chat_id
+            AND deleted_at IS NULL
+    `
+	if changed {
+		q = `
+        UPDATE
+            telegram_private_chat
+        SET
+            username = :username,
+            name = :name,
+            bio = :bio,
+            language_code = :language_code,
+            last_seen_at = :last_seen_at,
+            updated_at = :updated_at
+        WHERE
+            chat_id = :chat_id
+            AND deleted_at IS NULL
+    `
+	}
+
+	res, err := ds.DB.NamedExecContext(ctx, q, fresh)
+	if err != nil {
+		return err
+	}
+
+	// nothing written, either stopped since it was read (its cache entry was
+	// invalidated & must stay so) or touched already within the same second
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return err
+	}
+	ds.cachePrivateChat(ctx, fresh)
+
+	return nil
+}
+
+// `current` with profile fields of `chat` applied & last seen at `now`,
+// `changed` tells whether any profile field differs
+func touchedPrivateChat(current, chat *PrivateChat, now time.Time) (fresh *PrivateChat, changed bool) {
+	// DATETIME has second precision
+	now = now.Truncate(time.Second)
+
+	fresh = new(PrivateChat)
+	*fresh = *current
+	fresh.Username = chat.Username
+	fresh.Name = chat.Name
+	fresh.LanguageCode = chat.LanguageCode
+	if len(chat.Bio) != 0 {
+		fresh.Bio = chat.Bio
+	}
+	fresh.LastSeenAt = &now
+
+	changed = fresh.Username != current.Username ||
+		fresh.Name != current.Name ||
+		fresh.Bio != current.Bio ||
+		fresh.LanguageCode != current.LanguageCode
+	if changed {
+		fresh.UpdatedAt = now
+	}
+
+	return fresh, changed
+}
+
+// Persist language chosen by the user, empty resets it to follow Telegram's
+// language_code
+func (ds *DataSource) SetPrivateChatLanguage(ctx context.Context, chatId int64, language string) (err error) {
+	ctx, done := startQuery(ctx, "SetPrivateChatLanguage")
+	defer func() { done(err) }()
+
+	q := `
+        UPDATE
+            telegram_private_chat
+        SET
+            language = ?
+        WHERE
+            chat_id = ?
+    `
+
+	if _, err := ds.DB.ExecContext(ctx, q, language, chatId); err != nil {
+		return err
+	}
+
+	ds.invalidatePrivateChat(ctx, chatId)
+
+	return nil
+}
+
+// Mark whether the user has blocked the bot, recorded as "blocked" or
+// "unblocked" subscription event
+func (ds *DataSource) SetPrivateChatBlocked(ctx context.Context, chatId int64, blocked bool) (err error) {
+	ctx, done := startQuery(ctx, "SetPrivateChatBlocked")
+	defer func() { done(err) }()
+
+	q := `
+        UPDATE
+            telegram_private_chat
+        SET
+            is_blocked = ?
+        WHERE
+            chat_id = ?
+    `
+
+	event := SUBSCRIPTION_UNBLOCKED
+	if blocked {
+		event = SUBSCRIPTION_BLOCKED
+	}
+
+	tx, err := ds.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, q, blocked, chatId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// unknown chat or already in that state, nothing happened
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		tx.Rollback()
+		return err
+	}
+
+	if err := insertSubscriptionEvent(ctx, tx, chatId, event); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	ds.invalidatePrivateChat(ctx, chatId)
+
+	return nil
+}
+
+// Soft-delete private chat, the profile is kept so /start can restore it.
+// Records a "stopped" subscription event.
+func (ds *DataSource) DeletePrivateChat(ctx context.Context, chatId int64) (err error) {
+	ctx, done := startQuery(ctx, "DeletePrivateChat")
+	defer func() { done(err) }()
+
 	var args []any
 	args = append(args, chatId)
 
 	q := `
-        DELETE FROM 
+        UPDATE
             telegram_private_chat
+        SET
+            deleted_at = UTC_TIMESTAMP(),
+            stopped_at = UTC_TIMESTAMP()
         WHERE
             chat_id = ?
+            AND deleted_at IS NULL
     `
 
-	tx, err := ds.DB.Beginx()
+	tx, err := ds.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, q, args...)
 	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// not registered or already stopped, nothing happened
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		tx.Rollback()
 		return err
 	}
 
-	if _, err := tx.Exec(q, args...); err != nil {
+	if err := insertSubscriptionEvent(ctx, tx, chatId, SUBSCRIPTION_STOPPED); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -96,43 +360,73 @@ func (ds *DataSource) DeletePrivateChat(chatId int64) error {
 		return err
 	}
 
+	ds.invalidatePrivateChat(ctx, chatId)
+
 	return nil
 }
 
-func (ds *DataSource) GetPrivateChatWithQueryFilter(filter QueryFilter) ([]PrivateChat, error) {
-	var res []PrivateChat
-	var err error
+func (ds *DataSource) GetPrivateChatWithQueryFilter(ctx context.Context, filter QueryFilter) (res []PrivateChat, err error) {
+	ctx, done := startQuery(ctx, "GetPrivateChatWithQueryFilter")
+	defer func() { done(err) }()
 
-	defaultQuery := `
-        SELECT
-            chat_id, username, name, bio
+	query := `
+        SELECT` + privateChatColumns + `
         FROM
             telegram_private_chat
+        WHERE
+            deleted_at IS NULL
     `
 
-	if filter != nil {
-		var columnFilter []string
-		var replacer []any
+	// default with no query filter will select all
+	where, replacer := filter.build()
+	if len(where) != 0 {
+		query += " AND " + strings.Join(where, " AND ")
+	}
 
-		// build the query filter
-		query := defaultQuery + " WHERE "
-		for k := range filter {
-			w := fmt.Sprintf("%s = ?", k)
-			columnFilter = append(columnFilter, w)
+	err = ds.DB.SelectContext(ctx, &res, query, replacer...)
 
-			// for query replacer
-			replacer = append(replacer, filter[k])
-		}
-		filterStr := strings.Join(columnFilter, " AND ")
+	return res, err
+}
 
-		// add query filter to the main query
-		query += filterStr
+// Get at most `limit` private chats with chat_id greater than `afterChatId`,
+// ordered by chat_id. Used for keyset pagination, pass 0 as `afterChatId` to
+// get the first page (private chat IDs are always positive user IDs).
+func (ds *DataSource) GetPrivateChatPage(ctx context.Context, filter QueryFilter, afterChatId int64, limit int) (res []PrivateChat, err error) {
+	ctx, done := startQuery(ctx, "GetPrivateChatPage")
+	defer func() { done(err) }()
+
+	query := `
+        SELECT` + privateChatColumns + `
+        FROM
+            telegram_private_chat
+        WHERE
+            chat_id > ?
+            AND deleted_at IS NULL
+    `
 
-		err = ds.DB.Select(&res, query, replacer...)
-	} else {
-		// default with no query filter will select all
-		err = ds.DB.Select(&res, defaultQuery)
+	where, replacer := filter.build()
+	if len(where) != 0 {
+		query += " AND " + strings.Join(where, " AND ")
 	}
+	query += " ORDER BY chat_id LIMIT ?"
+
+	args := append([]any{afterChatId}, replacer...)
+	args = append(args, limit)
+
+	err = ds.DB.SelectContext(ctx, &res, query, args...)
 
 	return res, err
 }
+
+// build the query filter into equal comparison clauses & its query replacer
+func (filter QueryFilter) build() (columnFilter []string, replacer []any) {
+	for k := range filter {
+		w := fmt.Sprintf("%s = ?", k)
+		columnFilter = append(columnFilter, w)
+
+		// for query replacer
+		replacer = append(replacer, filter[k])
+	}
+
+	return columnFilter, replacer
+}
@@ -1,9 +1,12 @@
 package datasource
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
 
@@ -11,9 +14,12 @@ import (
 )
 
 func TestGetPrivateChatWithQueryFilter(t *testing.T) {
-	cfg := config.LoadConfig()
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	db := initDB(&cfg)
-	ds := NewDataSource(&cfg, db, nil)
+	ds := NewDataSource(config.NewStore(cfg, config.Options{}), db, nil)
 
 	testFilter := []struct {
 		Name   string
@@ -39,7 +45,7 @@ func TestGetPrivateChatWithQueryFilter(t *testing.T) {
 
 	for _, test := range testFilter {
 		t.Run(test.Name, func(t *testing.T) {
-			if res, err := ds.GetPrivateChatWithQueryFilter(test.Filter); err != nil {
+			if res, err := ds.GetPrivateChatWithQueryFilter(context.Background(), test.Filter); err != nil {
 				t.Fatal(err)
 			} else {
 				debug.DebugStruct(res)
@@ -48,6 +54,31 @@ func TestGetPrivateChatWithQueryFilter(t *testing.T) {
 	}
 }
 
+func TestTouchedPrivateChat(t *testing.T) {
+	updated := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 1, 2, 3, 4, 5, 600, time.UTC)
+	current := &PrivateChat{ChatID: 1234, Username: "gabriel_s", Name: "Gabriel ", Bio: "hi", LanguageCode: "en", IsBlocked: true, UpdatedAt: updated}
+
+	t.Run("unchanged", func(t *testing.T) {
+		fresh, changed := touchedPrivateChat(current, &PrivateChat{ChatID: 1234, Username: "gabriel_s", Name: "Gabriel ", LanguageCode: "en"}, now)
+		assert.False(t, changed)
+		assert.Equal(t, "hi", fresh.Bio)
+		assert.Equal(t, updated, fresh.UpdatedAt)
+		assert.Equal(t, now.Truncate(time.Second), *fresh.LastSeenAt)
+
+		// only SetPrivateChatBlocked clears it
+		assert.True(t, fresh.IsBlocked)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		fresh, changed := touchedPrivateChat(current, &PrivateChat{ChatID: 1234, Username: "gabriel", Name: "Gabriel ", LanguageCode: "en"}, now)
+		assert.True(t, changed)
+		assert.Equal(t, "gabriel", fresh.Username)
+		assert.Equal(t, now.Truncate(time.Second), fresh.UpdatedAt)
+		assert.Equal(t, "gabriel_s", current.Username)
+	})
+}
+
 func initDB(cfg *config.AppConfig) *sqlx.DB {
 	dbConfig := &mysql.Config{
 		User:                 cfg.DB.User,
@@ -57,6 +88,18 @@ func initDB(cfg *config.AppConfig) *sqlx.DB {
 		DBName:               cfg.DB.Database,
 		AllowNativePasswords: true,
 		CheckConnLiveness:    true,
+		ParseTime:            true,
+		Loc:                  time.UTC,
+		Params:               map[string]string{"time_zone": "'+00:00'"},
 	}
 	return sqlx.MustConnect("mysql", dbConfig.FormatDSN())
 }
+
+func TestMessageFilterTime(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	where, args := MessageFilter{ChatID: 1234, From: from, To: to}.build()
+	assert.Equal(t, []string{"chat_id = ?", "sent_at >= ?", "sent_at < ?"}, where)
+	assert.Equal(t, []any{int64(1234), from, to}, args)
+}
@@ -0,0 +1,156 @@
+package datasource
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// message direction
+const (
+	MESSAGE_INBOUND  = "in"
+	MESSAGE_OUTBOUND = "out"
+)
+
+// filter for message search, zero values are ignored
+type MessageFilter struct {
+	ChatID    int64
+	Direction string
+	Command   string
+
+	// on the message's own time (sent_at), not when it was recorded
+	From time.Time
+	To   time.Time
+
+	// substring match on message text
+	Text string
+}
+
+// convert telegram message to history entry, caption is used for media
+// messages
+func NewMessage(msg *tgbotapi.Message, direction string) *Message {
+	text := msg.Text
+	if len(text) == 0 {
+		text = msg.Caption
+	}
+
+	return &Message{
+		ChatID:    msg.Chat.ID,
+		Direction: direction,
+		MessageID: int64(msg.MessageID),
+		Command:   msg.Command(),
+		Text:      text,
+		SentAt:    msg.Time().UTC(),
+	}
+}
+
+func (ds *DataSource) InsertMessage(ctx context.Context, msg *Message) (err error) {
+	ctx, done := startQuery(ctx, "InsertMessage")
+	defer func() { done(err) }()
+
+	q := `
+        INSERT INTO telegram_message
+            (chat_id, direction, message_id, command, text, sent_at, created_at)
+        VALUES
+            (:chat_id, :direction, :message_id, :command, :text, :sent_at, UTC_TIMESTAMP())
+    `
+
+	_, err = ds.DB.NamedExecContext(ctx, q, msg)
+
+	return err
+}
+
+// Search at most `limit` messages with id less than `beforeId`, newest first.
+// Pass 0 as `beforeId` to get the first page. Pages are keyed by id only, the
+// time filters narrow them by sent_at.
+func (ds *DataSource) SearchMessages(ctx context.Context, filter MessageFilter, beforeId int64, limit int) (res []Message, err error) {
+	ctx, done := startQuery(ctx, "SearchMessages")
+	defer func() { done(err) }()
+
+	query := `
+        SELECT
+            id, chat_id, direction, message_id, command, text, sent_at, created_at
+        FROM
+            telegram_message
+        WHERE
+            1 = 1
+    `
+
+	where, args := filter.build()
+	if beforeId != 0 {
+		where = append(where, "id < ?")
+		args = append(args, beforeId)
+	}
+
+	for i := range where {
+		query += " AND " + where[i]
+	}
+
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	err = ds.DB.SelectContext(ctx, &res, query, args...)
+
+	return res, err
+}
+
+// Delete messages recorded before `before`, returns number of deleted rows
+func (ds *DataSource) PurgeMessages(ctx context.Context, before time.Time) (deleted int64, err error) {
+	ctx, done := startQuery(ctx, "PurgeMessages")
+	defer func() { done(err) }()
+
+	q := `
+        DELETE FROM
+            telegram_message
+        WHERE
+            created_at < ?
+    `
+
+	res, err := ds.DB.ExecContext(ctx, q, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (filter MessageFilter) build() (where []string, replacer []any) {
+	if filter.ChatID != 0 {
+		where = append(where, "chat_id = ?")
+		replacer = append(replacer, filter.ChatID)
+	}
+
+	if len(filter.Direction) != 0 {
+		where = append(where, "direction = ?")
+		replacer = append(replacer, filter.Direction)
+	}
+
+	if len(filter.Command) != 0 {
+		where = append(where, "command = ?")
+		replacer = append(replacer, filter.Command)
+	}
+
+	if !filter.From.IsZero() {
+		where = append(where, "sent_at >= ?")
+		replacer = append(replacer, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where = append(where, "sent_at < ?")
+		replacer = append(replacer, filter.To)
+	}
+
+	if len(filter.Text) != 0 {
+		where = append(where, "text LIKE ?")
+		replacer = append(replacer, "%"+escapeLike(filter.Text)+"%")
+	}
+
+	return where, replacer
+}
+
+// escape LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
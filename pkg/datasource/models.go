@@ -1,8 +1,73 @@
 package datasource
 
+import "time"
+
 type PrivateChat struct {
-	ChatID   int64  `json:"chat_id" db:"chat_id"`
-	Username string `json:"username" db:"username"`
-	Name     string `json:"name" db:"name"`
-	Bio      string `json:"bio" db:"bio"`
+	ChatID       int64      `json:"chat_id" db:"chat_id"`
+	Username     string     `json:"username" db:"username" redact:"name"`
+	Name         string     `json:"name" db:"name" redact:"name"`
+	Bio          string     `json:"bio" db:"bio" redact:"text"`
+	LanguageCode string     `json:"language_code" db:"language_code"`
+	Language     string     `json:"language" db:"language"`
+	IsBlocked    bool       `json:"is_blocked" db:"is_blocked"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	LastSeenAt   *time.Time `json:"last_seen_at" db:"last_seen_at"`
+	StartedAt    *time.Time `json:"started_at" db:"started_at"`
+	StoppedAt    *time.Time `json:"stopped_at" db:"stopped_at"`
+}
+
+type SubscriptionEvent struct {
+	ID        int64     `json:"id" db:"id"`
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Event     string    `json:"event" db:"event"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Message struct {
+	ID        int64     `json:"id" db:"id"`
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Direction string    `json:"direction" db:"direction"`
+	MessageID int64     `json:"message_id" db:"message_id"`
+	Command   string    `json:"command" db:"command"`
+	Text      string    `json:"text" db:"text" redact:"text"`
+	SentAt    time.Time `json:"sent_at" db:"sent_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type ChatRole struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Role      string    `json:"role" db:"role"`
+	GrantedBy int64     `json:"granted_by" db:"granted_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// `BannedBy` & `AllowedBy` are 0 when done through the gRPC controller
+type ChatBan struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Reason    string    `json:"reason" db:"reason"`
+	BannedBy  int64     `json:"banned_by" db:"banned_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type ChatAllow struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	AllowedBy int64     `json:"allowed_by" db:"allowed_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type AuditEvent struct {
+	ID        int64  `json:"id" db:"id"`
+	RequestID string `json:"request_id" db:"request_id"`
+	Caller    string `json:"caller" db:"caller"`
+	Peer      string `json:"peer" db:"peer"`
+	Method    string `json:"method" db:"method"`
+
+	// request as JSON, message text & file data redacted
+	Params string `json:"params" db:"params"`
+
+	// gRPC status code, e.g. OK or InvalidArgument
+	Code      string    `json:"code" db:"code"`
+	Error     string    `json:"error" db:"error"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
@@ -0,0 +1,121 @@
+package datasource
+
+import "context"
+
+// chat roles, admin can do everything a moderator can
+const (
+	ROLE_ADMIN     = "admin"
+	ROLE_MODERATOR = "moderator"
+)
+
+var ROLES = []string{ROLE_ADMIN, ROLE_MODERATOR}
+
+func IsRole(role string) bool {
+	for _, r := range ROLES {
+		if role == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Whether chat has `role`, either granted or by being admin. Admins listed in
+// config have every role.
+func (ds *DataSource) HasChatRole(ctx context.Context, chatId int64, role string) (bool, error) {
+	for _, id := range ds.Config.Get().Telegram.Admins {
+		if id == chatId {
+			return true, nil
+		}
+	}
+
+	roles, err := ds.GetChatRoles(ctx, chatId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range roles {
+		if r == role || r == ROLE_ADMIN {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Get roles granted to chat, config admins are not included. Cached.
+func (ds *DataSource) GetChatRoles(ctx context.Context, chatId int64) (res []string, err error) {
+	if ds.getCached(ctx, chatRolesCacheKey(chatId), &res) {
+		return res, nil
+	}
+
+	if res, err = ds.getChatRolesFromDB(ctx, chatId); err == nil {
+		ds.setCached(ctx, chatRolesCacheKey(chatId), res)
+	}
+
+	return res, err
+}
+
+func (ds *DataSource) getChatRolesFromDB(ctx context.Context, chatId int64) (res []string, err error) {
+	ctx, done := startQuery(ctx, "GetChatRoles")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            role
+        FROM
+            telegram_chat_role
+        WHERE
+            chat_id = ?
+    `
+
+	err = ds.DB.SelectContext(ctx, &res, q, chatId)
+
+	return res, err
+}
+
+// Grant role to chat, granting it again only updates `granted_by`
+func (ds *DataSource) GrantChatRole(ctx context.Context, role *ChatRole) (err error) {
+	ctx, done := startQuery(ctx, "GrantChatRole")
+	defer func() { done(err) }()
+
+	q := `
+        INSERT INTO telegram_chat_role
+            (chat_id, role, granted_by, created_at)
+        VALUES
+            (:chat_id, :role, :granted_by, UTC_TIMESTAMP())
+        ON DUPLICATE KEY UPDATE
+            granted_by = VALUES(granted_by)
+    `
+
+	if _, err = ds.DB.NamedExecContext(ctx, q, role); err != nil {
+		return err
+	}
+	ds.invalidate(ctx, chatRolesCacheKey(role.ChatID))
+
+	return nil
+}
+
+// Revoke role from chat, returns false if it wasn't granted
+func (ds *DataSource) RevokeChatRole(ctx context.Context, chatId int64, role string) (revoked bool, err error) {
+	ctx, done := startQuery(ctx, "RevokeChatRole")
+	defer func() { done(err) }()
+
+	q := `
+        DELETE FROM
+            telegram_chat_role
+        WHERE
+            chat_id = ?
+            AND role = ?
+    `
+
+	res, err := ds.DB.ExecContext(ctx, q, chatId, role)
+	if err != nil {
+		return false, err
+	}
+	ds.invalidate(ctx, chatRolesCacheKey(chatId))
+
+	n, err := res.RowsAffected()
+
+	return n != 0, err
+}
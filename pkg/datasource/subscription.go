@@ -0,0 +1,150 @@
+package datasource
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// subscription event types
+const (
+	SUBSCRIPTION_STARTED   = "started"
+	SUBSCRIPTION_STOPPED   = "stopped"
+	SUBSCRIPTION_BLOCKED   = "blocked"
+	SUBSCRIPTION_UNBLOCKED = "unblocked"
+)
+
+// filter for subscription event queries, zero values are ignored
+type SubscriptionEventFilter struct {
+	ChatID int64
+	Event  string
+	From   time.Time
+	To     time.Time
+}
+
+// written in the same transaction as the private chat change, so history
+// never goes out of sync with the profile
+func insertSubscriptionEvent(ctx context.Context, tx *sqlx.Tx, chatId int64, event string) error {
+	q := `
+        INSERT INTO telegram_subscription_event
+            (chat_id, event, created_at)
+        VALUES
+            (?, ?, UTC_TIMESTAMP())
+    `
+
+	_, err := tx.ExecContext(ctx, q, chatId, event)
+
+	return err
+}
+
+// Get at most `limit` subscription events with id greater than `afterId`,
+// ordered by id (oldest first)
+func (ds *DataSource) GetSubscriptionEvents(ctx context.Context, filter SubscriptionEventFilter, afterId int64, limit int) (res []SubscriptionEvent, err error) {
+	ctx, done := startQuery(ctx, "GetSubscriptionEvents")
+	defer func() { done(err) }()
+
+	query := `
+        SELECT
+            id, chat_id, event, created_at
+        FROM
+            telegram_subscription_event
+        WHERE
+            id > ?
+    `
+	args := []any{afterId}
+
+	where, replacer := filter.build()
+	for i := range where {
+		query += " AND " + where[i]
+	}
+	args = append(args, replacer...)
+
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, limit)
+
+	err = ds.DB.SelectContext(ctx, &res, query, args...)
+
+	return res, err
+}
+
+// Count subscription events grouped by event type
+func (ds *DataSource) CountSubscriptionEvents(ctx context.Context, filter SubscriptionEventFilter) (res map[string]uint64, err error) {
+	ctx, done := startQuery(ctx, "CountSubscriptionEvents")
+	defer func() { done(err) }()
+
+	var rows []struct {
+		Event string `db:"event"`
+		Count uint64 `db:"count"`
+	}
+
+	query := `
+        SELECT
+            event, COUNT(*) AS count
+        FROM
+            telegram_subscription_event
+        WHERE
+            1 = 1
+    `
+
+	where, replacer := filter.build()
+	for i := range where {
+		query += " AND " + where[i]
+	}
+	query += " GROUP BY event"
+
+	if err := ds.DB.SelectContext(ctx, &rows, query, replacer...); err != nil {
+		return nil, err
+	}
+
+	res = make(map[string]uint64)
+	for i := range rows {
+		res[rows[i].Event] = rows[i].Count
+	}
+
+	return res, nil
+}
+
+// Count chats that are registered & not blocking the bot
+func (ds *DataSource) CountActivePrivateChat(ctx context.Context) (count uint64, err error) {
+	ctx, done := startQuery(ctx, "CountActivePrivateChat")
+	defer func() { done(err) }()
+
+	q := `
+        SELECT
+            COUNT(*)
+        FROM
+            telegram_private_chat
+        WHERE
+            deleted_at IS NULL
+            AND is_blocked = FALSE
+    `
+
+	err = ds.DB.GetContext(ctx, &count, q)
+
+	return count, err
+}
+
+func (filter SubscriptionEventFilter) build() (where []string, replacer []any) {
+	if filter.ChatID != 0 {
+		where = append(where, "chat_id = ?")
+		replacer = append(replacer, filter.ChatID)
+	}
+
+	if len(filter.Event) != 0 {
+		where = append(where, "event = ?")
+		replacer = append(replacer, filter.Event)
+	}
+
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= ?")
+		replacer = append(replacer, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < ?")
+		replacer = append(replacer, filter.To)
+	}
+
+	return where, replacer
+}
@@ -6,8 +6,9 @@ import (
 	"reflect"
 )
 
+// Dump `obj` as indented JSON to stdout, fields tagged `redact` are redacted
 func DebugStruct(obj any) {
-	if b, err := json.MarshalIndent(obj, "", "  "); err == nil {
+	if b, err := json.MarshalIndent(Redact(obj), "", "  "); err == nil {
 		fmt.Println(reflect.TypeOf(obj).String(), string(b))
 	} else {
 		fmt.Println("Cannot debug:", reflect.TypeOf(obj))
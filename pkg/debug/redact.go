@@ -0,0 +1,280 @@
+package debug
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// values of the `redact` struct tag
+const (
+	REDACT_TAG = "redact"
+
+	// always replaced by REDACTED, e.g. passwords
+	REDACT_ALWAYS = "true"
+
+	// usernames & display names, masked when Mask.Names is set
+	REDACT_NAME = "name"
+
+	// message text, captions & bios, masked when Mask.Text is set
+	REDACT_TEXT = "text"
+
+	REDACTED = "[REDACTED]"
+)
+
+// Which PII is masked, set from `log.mask_*` in setting.yaml
+type Mask struct {
+	Names bool
+	Text  bool
+}
+
+var (
+	mask atomic.Pointer[Mask]
+
+	// whether a type has redacted fields, directly or nested
+	redactedTypes sync.Map
+)
+
+func SetMask(m Mask) {
+	mask.Store(&m)
+}
+
+func currentMask() Mask {
+	if m := mask.Load(); m != nil {
+		return *m
+	}
+
+	return Mask{}
+}
+
+// Copy of `v` to be JSON marshaled in place of it, with fields tagged
+// `redact` redacted or masked. Values of types without such fields are
+// returned as is.
+func Redact(v any) any {
+	if v == nil || !hasRedactedFields(reflect.TypeOf(v)) {
+		return v
+	}
+
+	return redactValue(reflect.ValueOf(v), currentMask())
+}
+
+func redactValue(v reflect.Value, m Mask) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// marshals itself, e.g. time.Time
+	if isMarshaler(v.Type()) || !hasRedactedFields(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), m)
+
+	case reflect.Struct:
+		out := make(map[string]any)
+		redactStruct(v, m, out)
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i), m)
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value(), m)
+		}
+		return out
+	}
+
+	return v.Interface()
+}
+
+// add fields of struct `v` to `out` by their JSON name, embedded structs are
+// flattened like encoding/json does
+func redactStruct(v reflect.Value, m Mask, out map[string]any) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, omitEmpty, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && len(name) == 0 {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				redactStruct(fv, m, out)
+				continue
+			}
+		}
+		if len(name) == 0 {
+			name = sf.Name
+		}
+
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if tag := fieldTag(t, sf); len(tag) != 0 {
+			out[name] = redactField(fv, tag, m)
+		} else {
+			out[name] = redactValue(fv, m)
+		}
+	}
+}
+
+func redactField(v reflect.Value, tag string, m Mask) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case tag == REDACT_NAME && !m.Names, tag == REDACT_TEXT && !m.Text:
+		return redactValue(v, m)
+
+	case v.Kind() != reflect.String:
+		return REDACTED
+
+	case tag == REDACT_NAME:
+		return maskName(v.String())
+
+	case tag == REDACT_TEXT:
+		return MaskText(v.String())
+	}
+
+	return REDACTED
+}
+
+// keep first character, so entries of the same user can still be told apart
+// roughly
+func maskName(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	return string(r) + strings.Repeat("*", utf8.RuneCountInString(s[size:]))
+}
+
+// keep length only
+func MaskText(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	return fmt.Sprintf("[%d chars]", utf8.RuneCountInString(s))
+}
+
+// Bot API URLs are `/bot<token>/<method>`, e.g. quoted in transport errors
+var botTokenPattern = regexp.MustCompile(`bot[0-9]+:[A-Za-z0-9_-]+`)
+
+// Replace Telegram bot tokens in `s`
+func RedactBotToken(s string) string {
+	return botTokenPattern.ReplaceAllString(s, "bot"+REDACTED)
+}
+
+// JSON name of exported field `sf`, empty for embedded structs without one.
+// `ok` is false for fields left out of JSON.
+func jsonName(sf reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	if !sf.IsExported() && !sf.Anonymous {
+		return "", false, false
+	}
+
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return parts[0], omitEmpty, true
+}
+
+// `redact` tag of field, falls back to tags of external types
+func fieldTag(t reflect.Type, sf reflect.StructField) string {
+	if tag, ok := sf.Tag.Lookup(REDACT_TAG); ok {
+		return tag
+	}
+
+	return externalTags[t][sf.Name]
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func isMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshaler) || t.Implements(textMarshaler)
+}
+
+func hasRedactedFields(t reflect.Type) bool {
+	if cached, ok := redactedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+
+	has := scanType(t, make(map[reflect.Type]bool))
+	redactedTypes.Store(t, has)
+
+	return has
+}
+
+// `visiting` breaks cycles, e.g. Message.ReplyToMessage
+func scanType(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] || isMarshaler(t) {
+		return false
+	}
+	visiting[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return scanType(t.Elem(), visiting)
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if len(fieldTag(t, sf)) != 0 || scanType(sf.Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
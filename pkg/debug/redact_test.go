@@ -0,0 +1,98 @@
+package debug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChat struct {
+	ChatID   int64      `json:"chat_id"`
+	Username string     `json:"username" redact:"name"`
+	Bio      string     `json:"bio,omitempty" redact:"text"`
+	Token    string     `json:"token" redact:"true"`
+	Password *string    `redact:"true"`
+	SeenAt   time.Time  `json:"seen_at"`
+	Internal string     `json:"-"`
+	Replies  []testChat `json:"replies,omitempty"`
+}
+
+// marshal redacted `v` back to generic JSON
+func redacted(t *testing.T, v any) map[string]any {
+	b, err := json.Marshal(Redact(v))
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(b, &out))
+
+	return out
+}
+
+func TestRedact(t *testing.T) {
+	defer SetMask(Mask{})
+
+	password := "hunter2"
+	chat := testChat{
+		ChatID:   1,
+		Username: "yeyee2901",
+		Bio:      "likes bidoof",
+		Token:    "123:abc",
+		Password: &password,
+		Internal: "x",
+		Replies:  []testChat{{Username: "bidoof"}},
+	}
+
+	SetMask(Mask{})
+	out := redacted(t, chat)
+	assert.Equal(t, REDACTED, out["token"])
+	assert.Equal(t, REDACTED, out["Password"])
+	assert.Equal(t, "yeyee2901", out["username"])
+	assert.Equal(t, "likes bidoof", out["bio"])
+	assert.Equal(t, float64(1), out["chat_id"])
+	assert.Contains(t, out, "seen_at")
+	assert.NotContains(t, out, "Internal")
+
+	SetMask(Mask{Names: true, Text: true})
+	out = redacted(t, &chat)
+	assert.Equal(t, "y********", out["username"])
+	assert.Equal(t, "[12 chars]", out["bio"])
+	assert.Equal(t, "b*****", out["replies"].([]any)[0].(map[string]any)["username"])
+
+	// original is untouched
+	assert.Equal(t, "yeyee2901", chat.Username)
+}
+
+func TestRedactTelegram(t *testing.T) {
+	defer SetMask(Mask{})
+	SetMask(Mask{Names: true, Text: true})
+
+	update := tgbotapi.Update{
+		UpdateID: 1,
+		Message: &tgbotapi.Message{
+			Text: "hello bidoof",
+			From: &tgbotapi.User{ID: 2, UserName: "yeyee2901"},
+			Chat: &tgbotapi.Chat{ID: 3, UserName: "yeyee2901"},
+		},
+	}
+
+	out := redacted(t, update)
+	msg := out["message"].(map[string]any)
+	assert.Equal(t, "[12 chars]", msg["text"])
+	assert.Equal(t, "y********", msg["from"].(map[string]any)["username"])
+	assert.Equal(t, "y********", msg["chat"].(map[string]any)["username"])
+
+	// embedded BaseChat is flattened
+	out = redacted(t, tgbotapi.NewMessage(3, "hello bidoof"))
+	assert.Equal(t, "[12 chars]", out["Text"])
+	assert.Equal(t, float64(3), out["ChatID"])
+}
+
+func TestRedactUntagged(t *testing.T) {
+	v := struct{ Name string }{"bidoof"}
+	assert.Equal(t, v, Redact(v))
+	assert.Nil(t, Redact(nil))
+}
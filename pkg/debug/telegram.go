@@ -0,0 +1,49 @@
+package debug
+
+import (
+	"reflect"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// `redact` tags of Bot API types, which can't carry struct tags of ours.
+// Field name to tag, by type.
+var externalTags = map[reflect.Type]map[string]string{
+	reflect.TypeOf(tgbotapi.User{}): {
+		"FirstName": REDACT_NAME,
+		"LastName":  REDACT_NAME,
+		"UserName":  REDACT_NAME,
+	},
+	reflect.TypeOf(tgbotapi.Chat{}): {
+		"Title":     REDACT_NAME,
+		"FirstName": REDACT_NAME,
+		"LastName":  REDACT_NAME,
+		"UserName":  REDACT_NAME,
+		"Bio":       REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.Contact{}): {
+		"FirstName":   REDACT_NAME,
+		"LastName":    REDACT_NAME,
+		"PhoneNumber": REDACT_ALWAYS,
+		"VCard":       REDACT_ALWAYS,
+	},
+	reflect.TypeOf(tgbotapi.Message{}): {
+		"Text":    REDACT_TEXT,
+		"Caption": REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.MessageConfig{}): {
+		"Text": REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.EditMessageTextConfig{}): {
+		"Text": REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.PhotoConfig{}): {
+		"Caption": REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.DocumentConfig{}): {
+		"Caption": REDACT_TEXT,
+	},
+	reflect.TypeOf(tgbotapi.BaseInputMedia{}): {
+		"Caption": REDACT_TEXT,
+	},
+}
@@ -0,0 +1,243 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// named placeholder values, `{name}` in a message is replaced with
+// args["name"]
+type Args map[string]any
+
+// plural forms, `Other` is mandatory. `Zero` & `One` fall back to `Other`
+// when not defined.
+type entry struct {
+	Zero  string
+	One   string
+	Other string
+}
+
+// Message catalog, one set of messages per locale
+type Catalog struct {
+	defaultLocale string
+	locales       map[string]map[string]entry
+}
+
+// Load every `<locale>.yaml`, `<locale>.yml` & `<locale>.json` file in `dir`.
+// The file name (without extension) is used as the locale tag.
+func LoadCatalog(dir, defaultLocale string) (*Catalog, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{
+		defaultLocale: normalize(defaultLocale),
+		locales:       make(map[string]map[string]entry),
+	}
+
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		locale := normalize(strings.TrimSuffix(f.Name(), ext))
+		if err := c.add(locale, b, ext == ".json"); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+	}
+
+	if _, ok := c.locales[c.defaultLocale]; !ok {
+		return nil, fmt.Errorf("catalog for default locale %q not found in %s", c.defaultLocale, dir)
+	}
+
+	return c, nil
+}
+
+// parse & add messages of a locale, value is either a string or a map of
+// plural forms
+func (c *Catalog) add(locale string, b []byte, isJSON bool) error {
+	raw := make(map[string]any)
+
+	var err error
+	if isJSON {
+		err = json.Unmarshal(b, &raw)
+	} else {
+		err = yaml.Unmarshal(b, &raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	messages := make(map[string]entry)
+	for key, val := range raw {
+		switch v := val.(type) {
+		case string:
+			messages[key] = entry{Other: v}
+
+		case map[string]any:
+			e, err := pluralEntry(key, v)
+			if err != nil {
+				return err
+			}
+			messages[key] = e
+
+		// yaml.v2 decodes nested maps with interface keys
+		case map[any]any:
+			m := make(map[string]any, len(v))
+			for k := range v {
+				m[fmt.Sprint(k)] = v[k]
+			}
+			e, err := pluralEntry(key, m)
+			if err != nil {
+				return err
+			}
+			messages[key] = e
+
+		default:
+			return fmt.Errorf("%s: message must be a string or plural forms", key)
+		}
+	}
+
+	c.locales[locale] = messages
+
+	return nil
+}
+
+func pluralEntry(key string, forms map[string]any) (entry, error) {
+	var e entry
+
+	for form, val := range forms {
+		s, ok := val.(string)
+		if !ok {
+			return e, fmt.Errorf("%s.%s: plural form must be a string", key, form)
+		}
+
+		switch form {
+		case "zero":
+			e.Zero = s
+		case "one":
+			e.One = s
+		case "other":
+			e.Other = s
+		default:
+			return e, fmt.Errorf("%s: unknown plural form %q", key, form)
+		}
+	}
+
+	if len(e.Other) == 0 {
+		return e, fmt.Errorf("%s: plural form \"other\" is required", key)
+	}
+
+	return e, nil
+}
+
+// Translate message `key` to `locale` (e.g. "en", "pt-br"), falling back to
+// the base language, then to the default locale, then to the key itself
+func (c *Catalog) T(locale, key string, args Args) string {
+	e, ok := c.lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	return format(e.Other, args)
+}
+
+// Same as T but picks plural form based on `count`, which is also available
+// as `{count}` placeholder
+func (c *Catalog) Plural(locale, key string, count int, args Args) string {
+	e, ok := c.lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	withCount := Args{"count": count}
+	for k := range args {
+		withCount[k] = args[k]
+	}
+
+	msg := e.Other
+	switch {
+	case count == 0 && len(e.Zero) != 0:
+		msg = e.Zero
+	case count == 1 && len(e.One) != 0:
+		msg = e.One
+	}
+
+	return format(msg, withCount)
+}
+
+// Resolve the locale that will be used for `locale`, empty if neither the
+// locale nor its base language is in the catalog
+func (c *Catalog) Match(locale string) string {
+	locale = normalize(locale)
+	if _, ok := c.locales[locale]; ok {
+		return locale
+	}
+
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := c.locales[base]; ok {
+			return base
+		}
+	}
+
+	return ""
+}
+
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Sorted list of available locales
+func (c *Catalog) Locales() []string {
+	var res []string
+	for locale := range c.locales {
+		res = append(res, locale)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (c *Catalog) lookup(locale, key string) (entry, bool) {
+	if matched := c.Match(locale); len(matched) != 0 {
+		if e, ok := c.locales[matched][key]; ok {
+			return e, true
+		}
+	}
+
+	e, ok := c.locales[c.defaultLocale][key]
+
+	return e, ok
+}
+
+// replace `{name}` placeholders, unknown placeholders are left as is
+func format(msg string, args Args) string {
+	if len(args) == 0 {
+		return msg
+	}
+
+	var oldnew []string
+	for k := range args {
+		oldnew = append(oldnew, "{"+k+"}", fmt.Sprint(args[k]))
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(msg)
+}
+
+// Telegram sends IETF tags like "en" or "pt-br", underscore is accepted too
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
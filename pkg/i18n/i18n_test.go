@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "en.yaml", `
+greet: "Hello {name}"
+apple:
+  zero: no apples
+  one: "{count} apple"
+  other: "{count} apples"
+only_en: english only
+`)
+	writeFile(t, dir, "pt.json", `{"greet": "Olá {name}", "apple": {"other": "{count} maçãs"}}`)
+
+	c, err := LoadCatalog(dir, "en")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	testCases := []struct {
+		Name   string
+		Actual string
+		Expect string
+	}{
+		{"named_placeholder", c.T("en", "greet", Args{"name": "Bidoof"}), "Hello Bidoof"},
+		{"other_locale", c.T("pt", "greet", Args{"name": "Bidoof"}), "Olá Bidoof"},
+		{"base_language", c.T("pt-BR", "greet", Args{"name": "Bidoof"}), "Olá Bidoof"},
+		{"unknown_locale", c.T("xx", "greet", Args{"name": "Bidoof"}), "Hello Bidoof"},
+		{"missing_key_falls_back", c.T("pt", "only_en", nil), "english only"},
+		{"missing_key", c.T("en", "nope", nil), "nope"},
+		{"unknown_placeholder", c.T("en", "greet", nil), "Hello {name}"},
+		{"plural_zero", c.Plural("en", "apple", 0, nil), "no apples"},
+		{"plural_one", c.Plural("en", "apple", 1, nil), "1 apple"},
+		{"plural_other", c.Plural("en", "apple", 2, nil), "2 apples"},
+		{"plural_other_only", c.Plural("pt", "apple", 1, nil), "1 maçãs"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expect, tc.Actual)
+		})
+	}
+
+	assert.Equal(t, []string{"en", "pt"}, c.Locales())
+}
+
+func TestLoadCatalogError(t *testing.T) {
+	t.Run("missing_default_locale", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "id.yaml", `greet: Halo`)
+
+		_, err := LoadCatalog(dir, "en")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("plural_without_other", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "en.yaml", "apple:\n  one: apple\n")
+
+		_, err := LoadCatalog(dir, "en")
+		assert.NotNil(t, err)
+	})
+}
+
+// every locale shipped with the bot must translate every message
+func TestSettingLocales(t *testing.T) {
+	c, err := LoadCatalog("../../setting/locales", "en")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	for _, locale := range c.Locales() {
+		for key := range c.locales[c.DefaultLocale()] {
+			_, ok := c.locales[locale][key]
+			assert.True(t, ok, "%s: missing %s", locale, key)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
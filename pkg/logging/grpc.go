@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadata carrying the request ID, taken from the caller if sent & echoed
+// back in response header
+const REQUEST_ID_METADATA = "x-request-id"
+
+// request IDs from callers must be hex or UUID, at most 64 chars (the audit
+// column size). Anything else is replaced by a generated one.
+var requestIdPattern = regexp.MustCompile(`^[0-9a-fA-F-]{1,64}$`)
+
+// Give every RPC a logger with request_id & method, must be the first
+// interceptor so the others log with it
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, requestId := withRequest(ctx, info.FullMethod)
+		grpc.SetHeader(ctx, metadata.Pairs(REQUEST_ID_METADATA, requestId))
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestId := withRequest(ss.Context(), info.FullMethod)
+		ss.SetHeader(metadata.Pairs(REQUEST_ID_METADATA, requestId))
+
+		return handler(srv, &loggedStream{ss, ctx})
+	}
+}
+
+type requestIdKey struct{}
+
+// ID of the RPC being handled, empty outside of one
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// handlers read the context from the stream, so it has to carry the logger
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func withRequest(ctx context.Context, method string) (context.Context, string) {
+	requestId := incomingRequestID(ctx)
+	if len(requestId) == 0 {
+		requestId = newRequestID()
+	}
+
+	ctx = context.WithValue(ctx, requestIdKey{}, requestId)
+	ctx = With(ctx, func(c zerolog.Context) zerolog.Context {
+		return c.Str("request_id", requestId).Str("method", method)
+	})
+
+	return ctx, requestId
+}
+
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(REQUEST_ID_METADATA); len(v) != 0 && requestIdPattern.MatchString(v[0]) {
+		return v[0]
+	}
+
+	return ""
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// log formats, set by `log.format` in setting.yaml
+const (
+	FORMAT_JSON    = "json"
+	FORMAT_CONSOLE = "console"
+)
+
+// Set up global logger writing to `logfile` (rotated), and to stdout as well
+// if configured. `log.Ctx(ctx)` falls back to it when ctx carries no request
+// logger.
+func Init(cfg config.AppConfig, logfile string) error {
+	if err := Apply(&cfg); err != nil {
+		return err
+	}
+
+	var out io.Writer = &lumberjack.Logger{
+		Filename:   logfile,
+		MaxSize:    100,
+		MaxBackups: 3,
+		MaxAge:     30,
+		Compress:   true,
+	}
+	if cfg.Log.Stdout {
+		out = zerolog.MultiLevelWriter(out, os.Stdout)
+	}
+
+	switch cfg.Log.Format {
+	case FORMAT_JSON, "":
+	case FORMAT_CONSOLE:
+		// no colors, escape codes would end up in the logfile
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: true}
+	default:
+		return fmt.Errorf("logging: unknown format %q", cfg.Log.Format)
+	}
+
+	zerolog.TimeFieldFormat = time.RFC3339
+	log.Logger = zerolog.New(out).With().Timestamp().Caller().Logger()
+	zerolog.DefaultContextLogger = &log.Logger
+
+	// `Interface` fields honor `redact` struct tags
+	zerolog.InterfaceMarshalFunc = func(v any) ([]byte, error) {
+		return json.Marshal(debug.Redact(v))
+	}
+
+	return nil
+}
+
+// Apply the parts of `cfg` that can change at runtime: level & PII masking
+func Apply(cfg *config.AppConfig) error {
+	apply, err := Reload(cfg)
+	if err != nil {
+		return err
+	}
+	apply()
+
+	return nil
+}
+
+// Config reload hook, see Apply
+func Reload(cfg *config.AppConfig) (func(), error) {
+	level, err := parseLevel(cfg.Log.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := debug.Mask{
+		Names: cfg.Log.MaskNames,
+		Text:  cfg.Log.MaskText,
+	}
+
+	return func() {
+		zerolog.SetGlobalLevel(level)
+		debug.SetMask(mask)
+	}, nil
+}
+
+// empty defaults to info
+func parseLevel(level string) (zerolog.Level, error) {
+	if len(level) == 0 {
+		return zerolog.InfoLevel, nil
+	}
+
+	l, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return l, fmt.Errorf("logging: %w", err)
+	}
+
+	return l, nil
+}
+
+// Add fields to the logger carried by ctx, every `log.Ctx(ctx)` entry down
+// the call chain gets them:
+//
+//	ctx = logging.With(ctx, func(c zerolog.Context) zerolog.Context {
+//		return c.Int64("chat_id", chatId)
+//	})
+func With(ctx context.Context, fields func(zerolog.Context) zerolog.Context) context.Context {
+	logger := fields(zerolog.Ctx(ctx).With()).Logger()
+
+	return logger.WithContext(ctx)
+}
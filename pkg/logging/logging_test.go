@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestInit(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "test.log")
+
+	testCases := []struct {
+		Name   string
+		Level  string
+		Format string
+		Valid  bool
+	}{
+		{"default", "", "", true},
+		{"debug console", "debug", FORMAT_CONSOLE, true},
+		{"unknown level", "verbose", FORMAT_JSON, false},
+		{"unknown format", "info", "xml", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var cfg config.AppConfig
+			cfg.Log.Level = tc.Level
+			cfg.Log.Format = tc.Format
+
+			err := Init(cfg, logfile)
+			if tc.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+}
+
+// decode the single entry logged through ctx
+func logEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	return entry
+}
+
+func TestInterfaceRedacted(t *testing.T) {
+	var cfg config.AppConfig
+	cfg.Log.MaskText = true
+	require.NoError(t, Init(cfg, filepath.Join(t.TempDir(), "test.log")))
+	defer zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	defer debug.SetMask(debug.Mask{})
+
+	var buf bytes.Buffer
+	msg := struct {
+		Text     string `json:"text" redact:"text"`
+		Password string `json:"password" redact:"true"`
+	}{"hello bidoof", "hunter2"}
+	logger := zerolog.New(&buf)
+	logger.Info().Interface("sent", msg).Msg("test")
+
+	entry := logEntry(t, &buf)
+	assert.Equal(t, map[string]any{"text": "[12 chars]", "password": debug.REDACTED}, entry["sent"])
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).WithContext(context.Background())
+
+	ctx = With(ctx, func(c zerolog.Context) zerolog.Context {
+		return c.Int("update_id", 7)
+	})
+	ctx = With(ctx, func(c zerolog.Context) zerolog.Context {
+		return c.Str("command", "start")
+	})
+	zerolog.Ctx(ctx).Info().Msg("test")
+
+	entry := logEntry(t, &buf)
+	assert.Equal(t, float64(7), entry["update_id"])
+	assert.Equal(t, "start", entry["command"])
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/GetBotStatus"}
+
+	testCases := []struct {
+		Name      string
+		RequestID string
+	}{
+		{"generated", ""},
+		{"from caller", "abc-123"},
+		{"uuid", "0b7c6f1e-3d7a-4c47-9a8e-2f4b1d6c9e10"},
+		{"too long", strings.Repeat("a", 65)},
+		{"not hex", "req_123"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := zerolog.New(&buf).WithContext(context.Background())
+			if len(tc.RequestID) != 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(REQUEST_ID_METADATA, tc.RequestID))
+			}
+
+			var requestId string
+			handler := func(ctx context.Context, req any) (any, error) {
+				zerolog.Ctx(ctx).Info().Msg("test")
+				requestId = RequestID(ctx)
+				return nil, nil
+			}
+
+			_, err := UnaryServerInterceptor()(ctx, nil, info, handler)
+			require.NoError(t, err)
+
+			entry := logEntry(t, &buf)
+			assert.Equal(t, info.FullMethod, entry["method"])
+			assert.Equal(t, requestId, entry["request_id"])
+			if requestIdPattern.MatchString(tc.RequestID) {
+				assert.Equal(t, tc.RequestID, entry["request_id"])
+			} else {
+				assert.Len(t, entry["request_id"], 32)
+			}
+		})
+	}
+}
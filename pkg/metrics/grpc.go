@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "gRPC requests handled, by full method name & status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request latency, by full method name. Streams are measured until they end.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	grpcInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "grpc",
+		Name:      "requests_in_flight",
+		Help:      "gRPC requests being handled, by full method name.",
+	}, []string{"method"})
+)
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := observeGrpc(info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := observeGrpc(info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+
+		return err
+	}
+}
+
+// start observing a request, the returned function records its result
+func observeGrpc(method string) func(error) {
+	start := time.Now()
+	grpcInFlight.WithLabelValues(method).Inc()
+
+	return func(err error) {
+		grpcInFlight.WithLabelValues(method).Dec()
+		grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	}
+}
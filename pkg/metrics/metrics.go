@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+)
+
+const NAMESPACE = "bidoof"
+
+// update handling, bot only
+var (
+	UpdatesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "bot",
+		Name:      "updates_total",
+		Help:      "Updates received, by update type & command.",
+	}, []string{"type", "command"})
+
+	UpdateDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "bot",
+		Name:      "update_duration_seconds",
+		Help:      "Time to handle an update, by update type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	UpdateTimeouts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "bot",
+		Name:      "update_timeouts_total",
+		Help:      "Updates whose handler exceeded the bot timeout.",
+	})
+
+	UpdatePanics = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "bot",
+		Name:      "update_panics_total",
+		Help:      "Updates whose handler panicked.",
+	})
+)
+
+// Bot API calls, both binaries
+var (
+	telegramRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "telegram",
+		Name:      "requests_total",
+		Help:      "Bot API requests, by method & HTTP status code (Telegram error code on failure, 0 if no response).",
+	}, []string{"method", "code"})
+
+	telegramDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "telegram",
+		Name:      "request_duration_seconds",
+		Help:      "Bot API request latency, by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	telegramInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "telegram",
+		Name:      "outbound_in_flight",
+		Help:      "Bot API requests waiting for Telegram to respond.",
+	})
+)
+
+// database
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: NAMESPACE,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Database query latency, by datasource operation.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation"})
+
+// Start serving `/metrics` on `listener` in background, empty listener
+// disables it
+func Serve(listener string) {
+	if len(listener) == 0 {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		log.Info().Str("listener", listener).Msg("metrics.start")
+		if err := http.ListenAndServe(listener, mux); err != nil {
+			log.Error().Err(err).Msg("metrics.serve")
+		}
+	}()
+}
+
+// Observe time since `start` on `o`, meant to be deferred
+func ObserveDuration(o prometheus.Observer, start time.Time) {
+	o.Observe(time.Since(start).Seconds())
+}
+
+// Observe latency of a database operation, meant to be deferred:
+//
+//	defer metrics.ObserveQuery("GetPrivateChat", time.Now())
+func ObserveQuery(operation string, start time.Time) {
+	queryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// Expose length of the update channel, i.e. updates received from Telegram but
+// not handled yet
+func WatchUpdateQueue(depth func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "bot",
+		Name:      "update_queue_depth",
+		Help:      "Updates received but not handled yet.",
+	}, func() float64 {
+		return float64(depth())
+	})
+}
+
+// Expose cache counters by result (hit, miss, ...), read from `stats` on
+// every scrape
+func WatchCache(stats func() map[string]uint64) {
+	prometheus.MustRegister(&cacheCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(NAMESPACE, "cache", "lookups_total"),
+			"Private chat cache lookups, by result.",
+			[]string{"result"}, nil,
+		),
+		stats: stats,
+	})
+}
+
+type cacheCollector struct {
+	desc  *prometheus.Desc
+	stats func() map[string]uint64
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for result, n := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(n), result)
+	}
+}
+
+// HTTP client for tgbotapi.NewBotAPIWithClient that records every Bot API
+// request
+type TelegramClient struct {
+	Client *http.Client
+}
+
+func NewTelegramClient() *TelegramClient {
+	return &TelegramClient{&http.Client{}}
+}
+
+func (c *TelegramClient) Do(req *http.Request) (*http.Response, error) {
+	method := telegramMethod(req.URL.Path)
+
+	telegramInFlight.Inc()
+	defer telegramInFlight.Dec()
+
+	start := time.Now()
+	resp, err := c.Client.Do(req)
+	telegramDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	// transport errors quote the URL, keep the token out of every log, span &
+	// status they end up in
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = debug.RedactBotToken(urlErr.URL)
+	}
+
+	code := 0
+	if err == nil {
+		code = resp.StatusCode
+	}
+	telegramRequests.WithLabelValues(method, strconv.Itoa(code)).Inc()
+
+	return resp, err
+}
+
+// path is `/bot<token>/<method>`, the token must never end up in a label
+func telegramMethod(path string) string {
+	return path[strings.LastIndexByte(path, '/')+1:]
+}
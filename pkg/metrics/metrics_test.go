@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTelegramClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/bot123:SECRET/sendMessage", nil)
+	resp, err := NewTelegramClient().Do(req)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(telegramRequests.WithLabelValues("sendMessage", "403")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(telegramInFlight))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/BotStatus"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+
+	_, err := UnaryServerInterceptor()(context.Background(), nil, info, handler)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcRequests.WithLabelValues(info.FullMethod, "NotFound")))
+}
+
+func TestWatchCache(t *testing.T) {
+	WatchCache(func() map[string]uint64 {
+		return map[string]uint64{"hit": 3, "miss": 1}
+	})
+
+	expected := `
+# HELP bidoof_cache_lookups_total Private chat cache lookups, by result.
+# TYPE bidoof_cache_lookups_total counter
+bidoof_cache_lookups_total{result="hit"} 3
+bidoof_cache_lookups_total{result="miss"} 1
+`
+	err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "bidoof_cache_lookups_total")
+	assert.Nil(t, err)
+}
+
+func TestTelegramClientRedactsToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/bot123:SECRET/getMe", nil)
+	_, err := NewTelegramClient().Do(req)
+	if assert.NotNil(t, err) {
+		assert.NotContains(t, err.Error(), "SECRET")
+	}
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Builds plain text together with its formatting entities, so the message can
+// be sent without parse mode and nothing ever needs escaping. Offsets are
+// counted in UTF-16 code units as required by Telegram.
+type EntityBuilder struct {
+	text     strings.Builder
+	offset   int
+	entities []tgbotapi.MessageEntity
+}
+
+func NewEntityBuilder() *EntityBuilder {
+	return new(EntityBuilder)
+}
+
+// Append unformatted text
+func (b *EntityBuilder) Text(s string) *EntityBuilder {
+	b.text.WriteString(s)
+	b.offset += UTF16Len(s)
+
+	return b
+}
+
+// Append text formatted as entity `entityType` (e.g. "bold", "code")
+func (b *EntityBuilder) Entity(entityType, s string) *EntityBuilder {
+	return b.entity(tgbotapi.MessageEntity{Type: entityType}, s)
+}
+
+func (b *EntityBuilder) Bold(s string) *EntityBuilder {
+	return b.Entity("bold", s)
+}
+
+func (b *EntityBuilder) Italic(s string) *EntityBuilder {
+	return b.Entity("italic", s)
+}
+
+func (b *EntityBuilder) Underline(s string) *EntityBuilder {
+	return b.Entity("underline", s)
+}
+
+func (b *EntityBuilder) Strikethrough(s string) *EntityBuilder {
+	return b.Entity("strikethrough", s)
+}
+
+func (b *EntityBuilder) Spoiler(s string) *EntityBuilder {
+	return b.Entity("spoiler", s)
+}
+
+func (b *EntityBuilder) Code(s string) *EntityBuilder {
+	return b.Entity("code", s)
+}
+
+// Append code block, `language` may be empty
+func (b *EntityBuilder) Pre(s, language string) *EntityBuilder {
+	return b.entity(tgbotapi.MessageEntity{Type: "pre", Language: language}, s)
+}
+
+// Append text linking to `url`
+func (b *EntityBuilder) Link(s, url string) *EntityBuilder {
+	return b.entity(tgbotapi.MessageEntity{Type: "text_link", URL: url}, s)
+}
+
+// Append text mentioning a user that may not have a username
+func (b *EntityBuilder) Mention(s string, user *tgbotapi.User) *EntityBuilder {
+	return b.entity(tgbotapi.MessageEntity{Type: "text_mention", User: user}, s)
+}
+
+func (b *EntityBuilder) entity(e tgbotapi.MessageEntity, s string) *EntityBuilder {
+	e.Offset = b.offset
+	e.Length = UTF16Len(s)
+
+	// zero length entities are rejected by Telegram
+	if e.Length != 0 {
+		b.entities = append(b.entities, e)
+	}
+
+	return b.Text(s)
+}
+
+// Returns the text & its entities, to be sent with PARSE_MODE_PLAIN
+func (b *EntityBuilder) Build() (string, []tgbotapi.MessageEntity) {
+	return b.text.String(), b.entities
+}
+
+// Length of `s` in UTF-16 code units
+func UTF16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityBuilder(t *testing.T) {
+	text, entities := NewEntityBuilder().
+		Text("🦫 ").
+		Bold("Bidoof").
+		Text(" says ").
+		Link("hi.", "https://example.com").
+		Code("").
+		Build()
+
+	assert.Equal(t, "🦫 Bidoof says hi.", text)
+	assert.Equal(t, []tgbotapi.MessageEntity{
+		// emoji outside BMP counts as 2 UTF-16 code units
+		{Type: "bold", Offset: 3, Length: 6},
+		{Type: "text_link", Offset: 15, Length: 3, URL: "https://example.com"},
+	}, entities)
+}
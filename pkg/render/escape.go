@@ -0,0 +1,40 @@
+package render
+
+import (
+	"html"
+	"strings"
+)
+
+// characters that must be escaped with `\` anywhere in MarkdownV2 text
+const MARKDOWN_V2_RESERVED = "\\_*[]()~`>#+-=|{}.!"
+
+var markdownV2Escaper = func() *strings.Replacer {
+	var oldnew []string
+	for _, c := range MARKDOWN_V2_RESERVED {
+		oldnew = append(oldnew, string(c), "\\"+string(c))
+	}
+
+	return strings.NewReplacer(oldnew...)
+}()
+
+// Escape text so it is displayed literally in MarkdownV2
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// Escape text so it is displayed literally in HTML parse mode
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// Escape text for the given Telegram parse mode, plain text is returned as is
+func Escape(parseMode ParseMode, s string) string {
+	switch parseMode {
+	case PARSE_MODE_MARKDOWN_V2:
+		return EscapeMarkdownV2(s)
+	case PARSE_MODE_HTML:
+		return EscapeHTML(s)
+	default:
+		return s
+	}
+}
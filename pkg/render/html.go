@@ -0,0 +1,166 @@
+package render
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// tags supported by Telegram HTML parse mode
+var htmlTags = map[string]bool{
+	"b": true, "strong": true,
+	"i": true, "em": true,
+	"u": true, "ins": true,
+	"s": true, "strike": true, "del": true,
+	"span": true, "tg-spoiler": true,
+	"a": true, "tg-emoji": true,
+	"code": true, "pre": true,
+	"blockquote": true,
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[a-zA-Z-]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'>]+))?)*)\s*>`)
+	htmlEntityPattern = regexp.MustCompile(`^&(?:lt|gt|amp|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+)
+
+type htmlTokenKind int
+
+const (
+	htmlText htmlTokenKind = iota
+
+	// `&...;` named or numeric entity
+	htmlEntity
+
+	// `<`, `>` or `&` that is not part of a tag or entity
+	htmlReserved
+
+	// supported opening or closing tag
+	htmlTag
+
+	// well-formed tag that Telegram doesn't support
+	htmlUnsupportedTag
+)
+
+type htmlToken struct {
+	kind    htmlTokenKind
+	text    string
+	pos     int
+	name    string
+	closing bool
+	paired  bool
+}
+
+// Split Telegram HTML into tokens, pairing opening & closing tags
+func tokenizeHTML(s string) []*htmlToken {
+	var (
+		tokens []*htmlToken
+		stack  []*htmlToken
+	)
+
+	textStart := -1
+	flushText := func(end int) {
+		if textStart != -1 {
+			tokens = append(tokens, &htmlToken{kind: htmlText, text: s[textStart:end], pos: textStart})
+			textStart = -1
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c != '<' && c != '>' && c != '&' {
+			if textStart == -1 {
+				textStart = i
+			}
+			i++
+			continue
+		}
+		flushText(i)
+
+		tok := &htmlToken{kind: htmlReserved, text: s[i : i+1], pos: i}
+		switch c {
+		case '&':
+			if m := htmlEntityPattern.FindString(s[i:]); len(m) != 0 {
+				tok.kind, tok.text = htmlEntity, m
+			}
+
+		case '<':
+			m := htmlTagPattern.FindStringSubmatch(s[i:])
+			if m == nil {
+				break
+			}
+
+			tok.text, tok.closing, tok.name = m[0], m[1] == "/", strings.ToLower(m[2])
+			if !htmlTags[tok.name] {
+				tok.kind = htmlUnsupportedTag
+				break
+			}
+			tok.kind = htmlTag
+
+			if !tok.closing {
+				stack = append(stack, tok)
+				break
+			}
+
+			// closing tag must close the innermost open tag
+			if len(stack) != 0 && stack[len(stack)-1].name == tok.name {
+				stack[len(stack)-1].paired = true
+				tok.paired = true
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		tokens = append(tokens, tok)
+		i += len(tok.text)
+	}
+	flushText(len(s))
+
+	return tokens
+}
+
+func escapeHTMLMarkup(s string) string {
+	var b strings.Builder
+
+	for _, tok := range tokenizeHTML(s) {
+		switch {
+		case tok.kind == htmlText, tok.kind == htmlEntity, tok.paired:
+			b.WriteString(tok.text)
+		default:
+			b.WriteString(EscapeHTML(tok.text))
+		}
+	}
+
+	return b.String()
+}
+
+func stripHTMLMarkup(s string) string {
+	var b strings.Builder
+
+	for _, tok := range tokenizeHTML(s) {
+		switch {
+		case tok.kind == htmlEntity:
+			b.WriteString(html.UnescapeString(tok.text))
+		case tok.paired:
+		default:
+			b.WriteString(tok.text)
+		}
+	}
+
+	return b.String()
+}
+
+func validateHTMLMarkup(s string) error {
+	for _, tok := range tokenizeHTML(s) {
+		switch {
+		case tok.kind == htmlReserved:
+			return &MarkupError{tok.pos, "character '" + tok.text + "' must be escaped as HTML entity"}
+		case tok.kind == htmlUnsupportedTag:
+			return &MarkupError{tok.pos, "tag <" + tok.name + "> is not supported"}
+		case tok.kind == htmlTag && !tok.paired && tok.closing:
+			return &MarkupError{tok.pos, "</" + tok.name + "> does not close the innermost open tag"}
+		case tok.kind == htmlTag && !tok.paired:
+			return &MarkupError{tok.pos, "<" + tok.name + "> is not closed"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type mdTokenKind int
+
+const (
+	mdText mdTokenKind = iota
+
+	// `\x`, already escaped character
+	mdEscaped
+
+	// reserved character that is not part of any entity
+	mdReserved
+
+	// `*`, `_`, `__`, `~` or `||`
+	mdMarker
+
+	// `[` of a link
+	mdLinkOpen
+
+	// `](url)` of a link
+	mdLinkClose
+
+	// inline code or pre block, including the backticks
+	mdCode
+)
+
+type mdToken struct {
+	kind mdTokenKind
+	text string
+	pos  int
+
+	// entity delimiters are only kept if they have a matching pair
+	paired bool
+
+	// whether this delimiter opens the entity, when paired
+	opener bool
+}
+
+// Split MarkdownV2 into tokens, pairing entity delimiters like an actual
+// parser would. Delimiters without pair, or that cross another entity, are
+// left unpaired.
+func tokenizeMarkdownV2(s string) []*mdToken {
+	var (
+		tokens []*mdToken
+		stack  []*mdToken
+	)
+
+	// pair `tok` with the nearest open delimiter of the same text, anything
+	// opened after it is improperly nested and dropped from the stack
+	closeWith := func(tok *mdToken, opener string) bool {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].text == opener {
+				stack[i].paired = true
+				tok.paired = true
+				stack = stack[:i]
+				return true
+			}
+		}
+
+		return false
+	}
+
+	textStart := -1
+	flushText := func(end int) {
+		if textStart != -1 {
+			tokens = append(tokens, &mdToken{kind: mdText, text: s[textStart:end], pos: textStart})
+			textStart = -1
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		// reserved characters are all ASCII, everything else is text
+		if c >= utf8.RuneSelf || !strings.ContainsRune(MARKDOWN_V2_RESERVED, rune(c)) {
+			if textStart == -1 {
+				textStart = i
+			}
+			i++
+			continue
+		}
+		flushText(i)
+
+		tok := &mdToken{kind: mdReserved, text: s[i : i+1], pos: i}
+		switch c {
+		case '\\':
+			// any ASCII character can be escaped
+			if i+1 < len(s) && s[i+1] > 0 && s[i+1] < utf8.RuneSelf {
+				tok.kind, tok.text = mdEscaped, s[i:i+2]
+			}
+
+		case '`':
+			if end := findCodeEnd(s, i); end != -1 {
+				tok.kind, tok.text = mdCode, s[i:end]
+			}
+
+		case '*', '~':
+			tok.kind = mdMarker
+
+		case '_':
+			tok.kind = mdMarker
+			if strings.HasPrefix(s[i:], "__") {
+				tok.text = "__"
+			}
+
+		case '|':
+			if strings.HasPrefix(s[i:], "||") {
+				tok.kind, tok.text = mdMarker, "||"
+			}
+
+		case '[':
+			tok.kind, tok.opener = mdLinkOpen, true
+			stack = append(stack, tok)
+
+		case ']':
+			if end := findLinkEnd(s, i); end != -1 {
+				linkClose := &mdToken{kind: mdLinkClose, text: s[i:end], pos: i}
+				if closeWith(linkClose, "[") {
+					tok = linkClose
+				}
+			}
+		}
+
+		if tok.kind == mdMarker && !closeWith(tok, tok.text) {
+			tok.opener = true
+			stack = append(stack, tok)
+		}
+
+		tokens = append(tokens, tok)
+		i += len(tok.text)
+	}
+	flushText(len(s))
+
+	return tokens
+}
+
+// returns the index after the closing backtick(s) of code starting at `start`,
+// or -1 if it is never closed
+func findCodeEnd(s string, start int) int {
+	delim := "`"
+	if strings.HasPrefix(s[start:], "```") {
+		delim = "```"
+	}
+
+	for i := start + len(delim); i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], delim):
+			return i + len(delim)
+		}
+	}
+
+	return -1
+}
+
+// returns the index after `)` of `](url)` starting at `start`, or -1 if it
+// is not a link
+func findLinkEnd(s string, start int) int {
+	if !strings.HasPrefix(s[start:], "](") {
+		return -1
+	}
+
+	for i := start + 2; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ')':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// inside code & link urls only "`", ")" and "\" need escaping, so lone
+// backslashes are the only thing to fix
+func escapeBackslashes(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+1 < len(s) && strings.IndexByte("\\`)", s[i+1]) != -1 {
+			b.WriteString(s[i : i+2])
+			i++
+		} else {
+			b.WriteString(`\\`)
+		}
+	}
+
+	return b.String()
+}
+
+// reverse of escapeBackslashes, lone backslashes are kept as is
+func unescapeBackslashes(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\\`)", s[i+1]) != -1 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// offset of the first backslash in code or link url that doesn't escape
+// anything, -1 if none
+func loneBackslash(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			continue
+		}
+
+		if i+1 < len(s) && strings.IndexByte("\\`)", s[i+1]) != -1 {
+			i++
+		} else {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func escapeMarkdownV2Markup(s string) string {
+	var b strings.Builder
+
+	for _, tok := range tokenizeMarkdownV2(s) {
+		switch {
+		case tok.kind == mdText, tok.kind == mdEscaped:
+			b.WriteString(tok.text)
+
+		case tok.kind == mdCode:
+			b.WriteString(escapeBackslashes(tok.text))
+
+		case tok.kind == mdLinkClose && tok.paired:
+			b.WriteString("](" + escapeBackslashes(tok.text[2:len(tok.text)-1]) + ")")
+
+		case tok.paired:
+			b.WriteString(tok.text)
+
+		default:
+			b.WriteString(EscapeMarkdownV2(tok.text))
+		}
+	}
+
+	return b.String()
+}
+
+func stripMarkdownV2Markup(s string) string {
+	var b strings.Builder
+
+	for _, tok := range tokenizeMarkdownV2(s) {
+		switch {
+		case tok.kind == mdEscaped:
+			b.WriteString(tok.text[1:])
+
+		case tok.kind == mdCode:
+			delim := "`"
+			if strings.HasPrefix(tok.text, "```") {
+				delim = "```"
+			}
+			code := tok.text[len(delim) : len(tok.text)-len(delim)]
+
+			// drop language of pre block
+			if lang, rest, found := strings.Cut(code, "\n"); delim == "```" && found && !strings.ContainsAny(lang, " \t") {
+				code = rest
+			}
+			b.WriteString(unescapeBackslashes(code))
+
+		// keep the url visible since the link is gone
+		case tok.kind == mdLinkClose && tok.paired:
+			b.WriteString(" (" + unescapeBackslashes(tok.text[2:len(tok.text)-1]) + ")")
+
+		case tok.paired:
+
+		default:
+			b.WriteString(tok.text)
+		}
+	}
+
+	return b.String()
+}
+
+func validateMarkdownV2Markup(s string) error {
+	for _, tok := range tokenizeMarkdownV2(s) {
+		switch {
+		case tok.kind == mdReserved:
+			return &MarkupError{tok.pos, "character '" + tok.text + "' is reserved and must be escaped with '\\'"}
+		case tok.kind == mdMarker && !tok.paired:
+			return &MarkupError{tok.pos, "'" + tok.text + "' is not closed or improperly nested"}
+		case tok.kind == mdLinkOpen && !tok.paired:
+			return &MarkupError{tok.pos, "'[' is not followed by '](url)'"}
+		case tok.kind == mdCode || (tok.kind == mdLinkClose && tok.paired):
+			if i := loneBackslash(tok.text); i != -1 {
+				return &MarkupError{tok.pos + i, "'\\' inside code or link url must be escaped with '\\'"}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package render
+
+import "fmt"
+
+// Malformed markup, `Offset` is in bytes
+type MarkupError struct {
+	Offset int
+	Reason string
+}
+
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("malformed markup at offset %d: %s", e.Offset, e.Reason)
+}
+
+// Escape everything in `s` that is not part of a well-formed entity, so the
+// intended formatting is kept and the rest is displayed literally. Unlike
+// Escape, markup already in `s` is preserved.
+func EscapeMarkup(parseMode ParseMode, s string) string {
+	switch parseMode {
+	case PARSE_MODE_MARKDOWN_V2:
+		return escapeMarkdownV2Markup(s)
+	case PARSE_MODE_HTML:
+		return escapeHTMLMarkup(s)
+	default:
+		return s
+	}
+}
+
+// Remove markup from `s`, the result is meant to be sent as plain text
+func StripMarkup(parseMode ParseMode, s string) string {
+	switch parseMode {
+	case PARSE_MODE_MARKDOWN_V2:
+		return stripMarkdownV2Markup(s)
+	case PARSE_MODE_HTML:
+		return stripHTMLMarkup(s)
+	default:
+		return s
+	}
+}
+
+// Report the first markup problem that would make Telegram reject `s`, as
+// *MarkupError
+func ValidateMarkup(parseMode ParseMode, s string) error {
+	switch parseMode {
+	case PARSE_MODE_MARKDOWN_V2:
+		return validateMarkdownV2Markup(s)
+	case PARSE_MODE_HTML:
+		return validateHTMLMarkup(s)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownV2Markup(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		In     string
+		Escape string
+		Strip  string
+		Valid  bool
+	}{
+		{"plain_sentence", "Hello, world!", `Hello, world\!`, "Hello, world!", false},
+		{"already_valid", `*bold* and _italic_\.`, `*bold* and _italic_\.`, "bold and italic.", true},
+		{"link", "see [docs](https://example.com/a_b) now.", `see [docs](https://example.com/a_b) now\.`, "see docs (https://example.com/a_b) now.", false},
+		{"unclosed_marker", "2 * 3 = 6", `2 \* 3 \= 6`, "2 * 3 = 6", false},
+		{"nested", "*bold __underline__*", "*bold __underline__*", "bold underline", true},
+		{"crossing", "*a _b* c_", `*a \_b* c\_`, "a _b c_", false},
+		{"code_keeps_content", "run `a.b(c)` now", "run `a.b(c)` now", "run a.b(c) now", true},
+		{"code_lone_backslash", "`C:\\dir`", "`C:\\\\dir`", "C:\\dir", false},
+		{"pre_block", "```go\nx := 1\n```", "```go\nx := 1\n```", "x := 1\n", true},
+		{"spoiler_and_pipe", "||secret|| a|b", `||secret|| a\|b`, "secret a|b", false},
+		{"bracket_without_link", "[not a link]", `\[not a link\]`, "[not a link]", false},
+		{"unicode", "🦫 *Bidoof*!", `🦫 *Bidoof*\!`, "🦫 Bidoof!", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			escaped := EscapeMarkup(PARSE_MODE_MARKDOWN_V2, tc.In)
+			assert.Equal(t, tc.Escape, escaped)
+			assert.Equal(t, tc.Strip, StripMarkup(PARSE_MODE_MARKDOWN_V2, tc.In))
+
+			err := ValidateMarkup(PARSE_MODE_MARKDOWN_V2, tc.In)
+			assert.Equal(t, tc.Valid, err == nil, "%v", err)
+
+			// escaped output must always be valid & stable
+			assert.Nil(t, ValidateMarkup(PARSE_MODE_MARKDOWN_V2, escaped))
+			assert.Equal(t, escaped, EscapeMarkup(PARSE_MODE_MARKDOWN_V2, escaped))
+		})
+	}
+}
+
+func TestHTMLMarkup(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		In     string
+		Escape string
+		Strip  string
+		Valid  bool
+	}{
+		{"plain", "a < b & c", "a &lt; b &amp; c", "a < b & c", false},
+		{"valid", `<b>bold</b> <a href="https://example.com">link</a> &amp;`, `<b>bold</b> <a href="https://example.com">link</a> &amp;`, "bold link &", true},
+		{"unclosed", "<b>bold", "&lt;b&gt;bold", "<b>bold", false},
+		{"misnested", "<b><i>x</b></i>", "&lt;b&gt;<i>x&lt;/b&gt;</i>", "<b>x</b>", false},
+		{"unsupported_tag", "<div>x</div>", "&lt;div&gt;x&lt;/div&gt;", "<div>x</div>", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			escaped := EscapeMarkup(PARSE_MODE_HTML, tc.In)
+			assert.Equal(t, tc.Escape, escaped)
+			assert.Equal(t, tc.Strip, StripMarkup(PARSE_MODE_HTML, tc.In))
+
+			err := ValidateMarkup(PARSE_MODE_HTML, tc.In)
+			assert.Equal(t, tc.Valid, err == nil, "%v", err)
+
+			assert.Nil(t, ValidateMarkup(PARSE_MODE_HTML, escaped))
+		})
+	}
+}
+
+func TestMarkupError(t *testing.T) {
+	err := ValidateMarkup(PARSE_MODE_MARKDOWN_V2, "ok\\. not ok.")
+
+	var markupErr *MarkupError
+	if assert.ErrorAs(t, err, &markupErr) {
+		assert.Equal(t, 11, markupErr.Offset)
+	}
+}
@@ -0,0 +1,39 @@
+package render
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram parse mode, the value is what Telegram expects in `parse_mode`
+type ParseMode string
+
+const (
+	PARSE_MODE_PLAIN       ParseMode = ""
+	PARSE_MODE_MARKDOWN_V2 ParseMode = tgbotapi.ModeMarkdownV2
+	PARSE_MODE_HTML        ParseMode = tgbotapi.ModeHTML
+)
+
+func (m ParseMode) String() string {
+	if m == PARSE_MODE_PLAIN {
+		return "plain"
+	}
+
+	return string(m)
+}
+
+// Parse parse mode name as written in config or templates, case sensitive
+// like Telegram
+func ParseParseMode(s string) (ParseMode, error) {
+	switch s {
+	case "", "plain":
+		return PARSE_MODE_PLAIN, nil
+	case string(PARSE_MODE_MARKDOWN_V2):
+		return PARSE_MODE_MARKDOWN_V2, nil
+	case string(PARSE_MODE_HTML):
+		return PARSE_MODE_HTML, nil
+	default:
+		return PARSE_MODE_PLAIN, fmt.Errorf("unknown parse mode %q", s)
+	}
+}
@@ -0,0 +1,228 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// parse mode of a template is determined by its file extension
+var parseModes = map[string]ParseMode{
+	".md":   PARSE_MODE_MARKDOWN_V2,
+	".html": PARSE_MODE_HTML,
+	".txt":  PARSE_MODE_PLAIN,
+}
+
+// Values of this type are interpolated without escaping, use it (or the
+// `raw` template function) only for trusted markup
+type Raw string
+
+type Template struct {
+	Name      string
+	ParseMode ParseMode
+
+	tmpl *template.Template
+}
+
+// Localized templates, every value interpolated with `{{ }}` is escaped
+// according to the template's parse mode
+type Renderer struct {
+	defaultLocale string
+	templates     map[string]map[string]*Template
+}
+
+// Load templates from `dir`, laid out as `<dir>/<locale>/<name>.<md|html|txt>`.
+// Every template must exist in the default locale, so a missing translation
+// can always fall back to it.
+func LoadTemplates(dir, defaultLocale string) (*Renderer, error) {
+	localeDirs, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{
+		defaultLocale: normalize(defaultLocale),
+		templates:     make(map[string]map[string]*Template),
+	}
+
+	for _, d := range localeDirs {
+		if !d.IsDir() {
+			continue
+		}
+
+		locale := normalize(d.Name())
+		files, err := os.ReadDir(filepath.Join(dir, d.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		r.templates[locale] = make(map[string]*Template)
+		for _, f := range files {
+			ext := filepath.Ext(f.Name())
+			parseMode, ok := parseModes[ext]
+			if f.IsDir() || !ok {
+				continue
+			}
+
+			b, err := os.ReadFile(filepath.Join(dir, d.Name(), f.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			name := strings.TrimSuffix(f.Name(), ext)
+			t, err := Parse(name, parseMode, string(b))
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", d.Name(), f.Name(), err)
+			}
+
+			if _, exist := r.templates[locale][name]; exist {
+				return nil, fmt.Errorf("%s/%s: template %q is defined more than once", d.Name(), f.Name(), name)
+			}
+			r.templates[locale][name] = t
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) validate() error {
+	defaults, ok := r.templates[r.defaultLocale]
+	if !ok {
+		return fmt.Errorf("templates for default locale %q not found", r.defaultLocale)
+	}
+
+	for locale := range r.templates {
+		for name, t := range r.templates[locale] {
+			d, ok := defaults[name]
+			if !ok {
+				return fmt.Errorf("%s/%s: template has no %s counterpart", locale, name, r.defaultLocale)
+			}
+
+			// same message must be sent the same way regardless of language
+			if d.ParseMode != t.ParseMode {
+				return fmt.Errorf("%s/%s: parse mode %q differs from %s (%q)", locale, name, t.ParseMode, r.defaultLocale, d.ParseMode)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Render template `name` in `locale`, falling back to the base language then
+// the default locale. Returns the text & the parse mode to send it with.
+func (r *Renderer) Render(locale, name string, data any) (text string, parseMode ParseMode, err error) {
+	t, ok := r.lookup(locale, name)
+	if !ok {
+		return "", PARSE_MODE_PLAIN, fmt.Errorf("template %q not found", name)
+	}
+
+	text, err = t.Execute(data)
+
+	return text, t.ParseMode, err
+}
+
+func (r *Renderer) lookup(locale, name string) (*Template, bool) {
+	locale = normalize(locale)
+	candidates := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, r.defaultLocale)
+
+	for _, c := range candidates {
+		if t, ok := r.templates[c][name]; ok {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// Parse a single template, escaping is injected into every `{{ }}` action
+func Parse(name string, parseMode ParseMode, text string) (*Template, error) {
+	funcs := template.FuncMap{
+		"escape": func(v any) string {
+			if raw, ok := v.(Raw); ok {
+				return string(raw)
+			}
+
+			return Escape(parseMode, fmt.Sprint(v))
+		},
+		"raw": func(v any) Raw {
+			return Raw(fmt.Sprint(v))
+		},
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			injectEscape(t.Tree, t.Tree.Root)
+		}
+	}
+
+	return &Template{name, parseMode, tmpl}, nil
+}
+
+func (t *Template) Execute(data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// append `| escape` to every action that prints something, the same way
+// html/template does contextual escaping
+func injectEscape(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			injectEscape(tree, c)
+		}
+
+	case *parse.ActionNode:
+		// variable declaration {{ $x := ... }} prints nothing
+		if len(n.Pipe.Decl) != 0 {
+			return
+		}
+
+		escape := parse.NewIdentifier("escape").SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{escape},
+		})
+
+	case *parse.IfNode:
+		injectEscape(tree, n.List)
+		injectEscape(tree, n.ElseList)
+
+	case *parse.RangeNode:
+		injectEscape(tree, n.List)
+		injectEscape(tree, n.ElseList)
+
+	case *parse.WithNode:
+		injectEscape(tree, n.List)
+		injectEscape(tree, n.ElseList)
+	}
+}
+
+func normalize(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	data := map[string]any{
+		"Name":  "Bidoof (the 1st).",
+		"Link":  Raw("[site](https://example.com)"),
+		"Items": []string{"a.b", "c!"},
+	}
+
+	testCases := []struct {
+		Name      string
+		ParseMode ParseMode
+		Template  string
+		Expect    string
+	}{
+		{"markdown_escape", PARSE_MODE_MARKDOWN_V2, `*Hi* {{ .Name }}\!`, `*Hi* Bidoof \(the 1st\)\.\!`},
+		{"markdown_raw_type", PARSE_MODE_MARKDOWN_V2, `{{ .Link }}`, `[site](https://example.com)`},
+		{"markdown_raw_func", PARSE_MODE_MARKDOWN_V2, `{{ "_x_" | raw }}`, `_x_`},
+		{"markdown_range", PARSE_MODE_MARKDOWN_V2, `{{ range .Items }}\- {{ . }} {{ end }}`, `\- a\.b \- c\! `},
+		{"markdown_if_variable", PARSE_MODE_MARKDOWN_V2, `{{ $n := .Name }}{{ if $n }}{{ $n }}{{ end }}`, `Bidoof \(the 1st\)\.`},
+		{"html_escape", PARSE_MODE_HTML, `<b>{{ .Name }}</b> {{ "<i>" }}`, `<b>Bidoof (the 1st).</b> &lt;i&gt;`},
+		{"plain", PARSE_MODE_PLAIN, `{{ .Name }}`, `Bidoof (the 1st).`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tmpl, err := Parse(tc.Name, tc.ParseMode, tc.Template)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			text, err := tmpl.Execute(data)
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.Expect, text)
+			}
+		})
+	}
+
+	t.Run("missing_key", func(t *testing.T) {
+		tmpl, err := Parse("missing", PARSE_MODE_MARKDOWN_V2, `{{ .Nope }}`)
+		if assert.Nil(t, err) {
+			_, err = tmpl.Execute(data)
+			assert.NotNil(t, err)
+		}
+	})
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en"), "hello.md", `Hello {{ .Name }}\!`)
+	writeFile(t, filepath.Join(dir, "id"), "hello.md", `Halo {{ .Name }}\!`)
+
+	r, err := LoadTemplates(dir, "en")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	text, parseMode, err := r.Render("id", "hello", map[string]string{"Name": "a.b"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, `Halo a\.b\!`, text)
+		assert.Equal(t, PARSE_MODE_MARKDOWN_V2, parseMode)
+	}
+
+	// unknown locale falls back to default
+	text, _, err = r.Render("pt-BR", "hello", map[string]string{"Name": "a"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, `Hello a\!`, text)
+	}
+
+	_, _, err = r.Render("en", "nope", nil)
+	assert.NotNil(t, err)
+
+	t.Run("parse_mode_mismatch", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "id"), "hello.html", `<b>Halo</b>`)
+		defer os.Remove(filepath.Join(dir, "id", "hello.html"))
+
+		_, err := LoadTemplates(dir, "en")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("no_default_counterpart", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "id"), "bye.txt", `Dah`)
+		defer os.Remove(filepath.Join(dir, "id", "bye.txt"))
+
+		_, err := LoadTemplates(dir, "en")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("syntax_error", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "en"), "broken.txt", `{{ .Name `)
+		defer os.Remove(filepath.Join(dir, "en", "broken.txt"))
+
+		_, err := LoadTemplates(dir, "en")
+		assert.NotNil(t, err)
+	})
+}
+
+// templates shipped with the bot must load
+func TestSettingTemplates(t *testing.T) {
+	_, err := LoadTemplates("../../setting/templates", "en")
+	assert.Nil(t, err)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
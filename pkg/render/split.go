@@ -0,0 +1,382 @@
+package render
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram rejects text longer than this, in UTF-16 code units. Markup is
+// counted too, so chunks are always on the safe side.
+const MAX_MESSAGE_LENGTH = 4096
+
+// caption of a media can't be split, longer captions are rejected
+const MAX_CAPTION_LENGTH = 1024
+
+// entity that is still open at some offset of the text
+type openEntity struct {
+	open  string
+	close string
+
+	// entities like links can't be closed & reopened around a split
+	reopenable bool
+
+	// whitespace is meaningful inside code & pre
+	verbatim bool
+}
+
+// markup state before a byte offset of the text
+type cutPoint struct {
+	// false inside atomic markup (escape, code, tag, ...) or inside a rune
+	ok bool
+
+	// entities open at this offset, outermost first
+	open []openEntity
+}
+
+type chunkRange struct {
+	start  int
+	end    int
+	prefix string
+	suffix string
+}
+
+// Split `text` into chunks of at most `limit` UTF-16 code units, preferring
+// to break on paragraph, then line, then word boundaries outside of any
+// entity. When an entity alone is longer than `limit` it is closed at the end
+// of a chunk and reopened at the start of the next one. Entities that can't
+// be reopened (links, custom emoji) & are longer than `limit` can't be split,
+// a *MarkupError is returned for them before anything is sent.
+func Split(parseMode ParseMode, text string, limit int) ([]string, error) {
+	var points []cutPoint
+	switch parseMode {
+	case PARSE_MODE_MARKDOWN_V2:
+		points = markdownV2CutPoints(text)
+	case PARSE_MODE_HTML:
+		points = htmlCutPoints(text)
+	default:
+		points = plainCutPoints(text, nil)
+	}
+
+	ranges, err := splitRanges(text, points, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []string
+	for _, c := range ranges {
+		chunks = append(chunks, c.prefix+text[c.start:c.end]+c.suffix)
+	}
+
+	return chunks, nil
+}
+
+// Split plain `text` like Split, distributing `entities` to the chunks they
+// fall in. An entity spanning several chunks is split too.
+func SplitWithEntities(text string, entities []tgbotapi.MessageEntity, limit int) ([]string, [][]tgbotapi.MessageEntity) {
+	u16 := utf16Offsets(text)
+
+	var (
+		chunks        []string
+		chunkEntities [][]tgbotapi.MessageEntity
+	)
+	// entities are reopenable, so plain text can always be cut
+	ranges, _ := splitRanges(text, plainCutPoints(text, entities), limit)
+	for _, c := range ranges {
+		start, end := u16[c.start], u16[c.end]
+
+		var es []tgbotapi.MessageEntity
+		for _, e := range entities {
+			s, t := e.Offset, e.Offset+e.Length
+			if s < start {
+				s = start
+			}
+			if t > end {
+				t = end
+			}
+
+			if t > s {
+				e.Offset, e.Length = s-start, t-s
+				es = append(es, e)
+			}
+		}
+
+		chunks = append(chunks, text[c.start:c.end])
+		chunkEntities = append(chunkEntities, es)
+	}
+
+	return chunks, chunkEntities
+}
+
+func splitRanges(text string, points []cutPoint, limit int) ([]chunkRange, error) {
+	var (
+		chunks []chunkRange
+		u16    = utf16Offsets(text)
+		start  = 0
+	)
+
+	for start < len(text) {
+		prefix := reopen(points[start].open)
+		budget := limit - UTF16Len(prefix)
+
+		// the rest fits
+		if u16[len(text)]-u16[start] <= budget {
+			chunks = append(chunks, chunkRange{start, len(text), prefix, ""})
+			break
+		}
+
+		end, suffix, ok := bestCut(text, points, u16, start, budget)
+		if !ok {
+			return nil, &MarkupError{end, "entity longer than the message limit can't be split"}
+		}
+		chunk := chunkRange{start, end, prefix, suffix}
+
+		// separator whitespace is dropped, unless it's meaningful
+		if !verbatim(points[end].open) {
+			for chunk.end > chunk.start && isSpace(text[chunk.end-1]) {
+				chunk.end--
+			}
+			for end < len(text) && isSpace(text[end]) {
+				end++
+			}
+		}
+
+		if chunk.end > chunk.start {
+			chunks = append(chunks, chunk)
+		}
+		start = end
+	}
+
+	return chunks, nil
+}
+
+// pick the best offset to cut the chunk starting at `start`, returns the
+// offset & the markup needed to close entities still open there. Not ok when
+// the only cut left would break markup.
+func bestCut(text string, points []cutPoint, u16 []int, start, budget int) (int, string, bool) {
+	best, bestScore, bestSuffix := -1, -1, ""
+
+	for p := start + 1; p < len(text) && u16[p]-u16[start] <= budget; p++ {
+		if !points[p].ok {
+			continue
+		}
+
+		score := 0
+		if len(points[p].open) == 0 {
+			score = 10
+		} else if !reopenable(points[p].open) {
+			continue
+		}
+
+		suffix := closing(points[p].open)
+		if u16[p]-u16[start]+UTF16Len(suffix) > budget {
+			continue
+		}
+
+		switch {
+		case text[p-1] == '\n' && p-2 > start && text[p-2] == '\n':
+			score += 3
+		case text[p-1] == '\n':
+			score += 2
+		case text[p-1] == ' ':
+			score += 1
+		}
+
+		if score >= bestScore {
+			best, bestScore, bestSuffix = p, score, suffix
+		}
+	}
+
+	if best != -1 {
+		return best, bestSuffix, true
+	}
+
+	// nowhere safe to cut, cut as late as possible on rune boundary. Only fine
+	// outside of markup, e.g. a single rune wider than the budget.
+	p := start + 1
+	for p < len(text) && !utf8.RuneStart(text[p]) {
+		p++
+	}
+	for q := p; q < len(text) && u16[q]-u16[start] <= budget; q++ {
+		if utf8.RuneStart(text[q]) {
+			p = q
+		}
+	}
+
+	return p, "", points[p].ok && len(points[p].open) == 0
+}
+
+func plainCutPoints(text string, entities []tgbotapi.MessageEntity) []cutPoint {
+	u16 := utf16Offsets(text)
+	points := make([]cutPoint, len(text)+1)
+
+	// entities have no markup, so they can always be split. Only how many are
+	// open & whether code is among them matters: sweep them by start & end
+	// offset, building the open list only when it changes.
+	var starts, ends []tgbotapi.MessageEntity
+	for _, e := range entities {
+		if e.Length > 0 {
+			starts = append(starts, e)
+		}
+	}
+	ends = append(ends, starts...)
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Offset < starts[j].Offset })
+	sort.Slice(ends, func(i, j int) bool { return ends[i].Offset+ends[i].Length < ends[j].Offset+ends[j].Length })
+
+	var (
+		open                   []openEntity
+		nOpen, nVerbatim, s, e int
+	)
+	for p := range points {
+		if p < len(text) && !utf8.RuneStart(text[p]) {
+			continue
+		}
+
+		changed := false
+		for ; s < len(starts) && starts[s].Offset < u16[p]; s++ {
+			nOpen, nVerbatim, changed = nOpen+1, nVerbatim+verbatimEntity(starts[s]), true
+		}
+		for ; e < len(ends) && ends[e].Offset+ends[e].Length <= u16[p]; e++ {
+			nOpen, nVerbatim, changed = nOpen-1, nVerbatim-verbatimEntity(ends[e]), true
+		}
+
+		if changed {
+			open = nil
+			for i := 0; i < nOpen; i++ {
+				open = append(open, openEntity{reopenable: true, verbatim: i < nVerbatim})
+			}
+		}
+		points[p] = cutPoint{true, open}
+	}
+
+	return points
+}
+
+func verbatimEntity(e tgbotapi.MessageEntity) int {
+	if e.Type == "code" || e.Type == "pre" {
+		return 1
+	}
+
+	return 0
+}
+
+func markdownV2CutPoints(text string) []cutPoint {
+	points := make([]cutPoint, len(text)+1)
+
+	var stack []openEntity
+	for _, tok := range tokenizeMarkdownV2(text) {
+		open := append([]openEntity(nil), stack...)
+		points[tok.pos] = cutPoint{true, open}
+
+		switch {
+		case tok.kind == mdText:
+			for p := tok.pos; p < tok.pos+len(tok.text); p++ {
+				points[p] = cutPoint{utf8.RuneStart(text[p]), open}
+			}
+
+		case tok.kind == mdMarker && tok.paired && tok.opener:
+			stack = append(open, openEntity{tok.text, tok.text, true, false})
+
+		case tok.kind == mdLinkOpen && tok.paired:
+			stack = append(open, openEntity{tok.text, "", false, false})
+
+		case tok.paired && len(stack) != 0:
+			stack = open[:len(open)-1]
+		}
+	}
+	points[len(text)] = cutPoint{true, stack}
+
+	return points
+}
+
+func htmlCutPoints(text string) []cutPoint {
+	points := make([]cutPoint, len(text)+1)
+
+	var stack []openEntity
+	for _, tok := range tokenizeHTML(text) {
+		open := append([]openEntity(nil), stack...)
+		points[tok.pos] = cutPoint{true, open}
+
+		switch {
+		case tok.kind == htmlText:
+			for p := tok.pos; p < tok.pos+len(tok.text); p++ {
+				points[p] = cutPoint{utf8.RuneStart(text[p]), open}
+			}
+
+		case tok.kind == htmlTag && tok.paired && !tok.closing:
+			stack = append(open, openEntity{tok.text, "</" + tok.name + ">", tok.name != "tg-emoji", tok.name == "code" || tok.name == "pre"})
+
+		case tok.kind == htmlTag && tok.paired && len(stack) != 0:
+			stack = open[:len(open)-1]
+		}
+	}
+	points[len(text)] = cutPoint{true, stack}
+
+	return points
+}
+
+func reopenable(open []openEntity) bool {
+	for _, e := range open {
+		if !e.reopenable {
+			return false
+		}
+	}
+
+	return true
+}
+
+func verbatim(open []openEntity) bool {
+	for _, e := range open {
+		if e.verbatim {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reopen(open []openEntity) string {
+	var b strings.Builder
+	for _, e := range open {
+		b.WriteString(e.open)
+	}
+
+	return b.String()
+}
+
+func closing(open []openEntity) string {
+	var b strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString(open[i].close)
+	}
+
+	return b.String()
+}
+
+// UTF-16 offset of every byte offset of `s`, bytes inside a rune get the
+// offset of the rune
+func utf16Offsets(s string) []int {
+	offsets := make([]int, len(s)+1)
+
+	n := 0
+	for i, r := range s {
+		size := utf8.RuneLen(r)
+		if size < 0 {
+			size = 1
+		}
+		for j := i; j < i+size && j < len(s); j++ {
+			offsets[j] = n
+		}
+		n += len(utf16.Encode([]rune{r}))
+	}
+	offsets[len(s)] = n
+
+	return offsets
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		ParseMode ParseMode
+		In        string
+		Limit     int
+		Out       []string
+	}{
+		{"fits", PARSE_MODE_PLAIN, "short", 10, []string{"short"}},
+		{"paragraph", PARSE_MODE_PLAIN, "aaaa bbbb\ncccc\n\ndddd", 18, []string{"aaaa bbbb\ncccc", "dddd"}},
+		{"line", PARSE_MODE_PLAIN, "aaaa bbbb\ncccc dddd", 16, []string{"aaaa bbbb", "cccc dddd"}},
+		{"word", PARSE_MODE_PLAIN, "aaaa bbbb cccc", 10, []string{"aaaa bbbb", "cccc"}},
+		{"hard_cut", PARSE_MODE_PLAIN, "aaaaaaaaaa", 4, []string{"aaaa", "aaaa", "aa"}},
+		{"surrogate_pair", PARSE_MODE_PLAIN, "🦫🦫🦫", 4, []string{"🦫🦫", "🦫"}},
+		{"md_avoid_entity", PARSE_MODE_MARKDOWN_V2, "aa *bb cc* dd", 11, []string{"aa *bb cc*", "dd"}},
+		{"md_reopen_entity", PARSE_MODE_MARKDOWN_V2, "*aaaa bbbb cccc*", 12, []string{"*aaaa bbbb*", "*cccc*"}},
+		{"md_keep_escape", PARSE_MODE_MARKDOWN_V2, `aaa\.\.\.`, 5, []string{`aaa\.`, `\.\.`}},
+		{"md_keep_link", PARSE_MODE_MARKDOWN_V2, "a [b c](https://x.y) d", 20, []string{"a", "[b c](https://x.y) d"}},
+		{"html_reopen_tag", PARSE_MODE_HTML, `<b class="x">aaaa bbbb</b>`, 23, []string{`<b class="x">aaaa</b>`, `<b class="x">bbbb</b>`}},
+		{"html_pre_whitespace", PARSE_MODE_HTML, "<pre>aa bb</pre>", 15, []string{"<pre>aa </pre>", "<pre>bb</pre>"}},
+		{"html_keep_entity", PARSE_MODE_HTML, "aa&amp;&amp;", 7, []string{"aa&amp;", "&amp;"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			chunks, err := Split(tc.ParseMode, tc.In, tc.Limit)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Out, chunks)
+
+			for _, c := range chunks {
+				assert.LessOrEqual(t, UTF16Len(c), tc.Limit)
+				if tc.ParseMode != PARSE_MODE_PLAIN && ValidateMarkup(tc.ParseMode, tc.In) == nil {
+					assert.Nil(t, ValidateMarkup(tc.ParseMode, c), c)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLongMessage(t *testing.T) {
+	paragraph := EscapeMarkdownV2(strings.Repeat("lorem ipsum dolor sit amet. ", 40))
+	text := strings.Repeat("*"+paragraph+"*\n\n", 10)
+
+	chunks, err := Split(PARSE_MODE_MARKDOWN_V2, text, MAX_MESSAGE_LENGTH)
+	require.NoError(t, err)
+	assert.Greater(t, len(chunks), 1)
+
+	for _, c := range chunks {
+		assert.LessOrEqual(t, UTF16Len(c), MAX_MESSAGE_LENGTH)
+		assert.Nil(t, ValidateMarkup(PARSE_MODE_MARKDOWN_V2, c))
+	}
+	assert.Equal(t, StripMarkup(PARSE_MODE_MARKDOWN_V2, strings.TrimSpace(text)), StripMarkup(PARSE_MODE_MARKDOWN_V2, strings.TrimSpace(strings.Join(chunks, "\n\n"))))
+}
+
+// entities that can't be reopened are never cut, splitting fails instead
+func TestSplitUnsplittable(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		ParseMode ParseMode
+		In        string
+	}{
+		{"md_link", PARSE_MODE_MARKDOWN_V2, "[aaaa bbbb cccc](https://x.y)"},
+		{"html_link", PARSE_MODE_HTML, `<a href="https://x.y">aaaa bbbb cccc</a>`},
+		{"html_emoji", PARSE_MODE_HTML, `<tg-emoji emoji-id="1">aaaa bbbb cccc</tg-emoji>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			chunks, err := Split(tc.ParseMode, tc.In, 12)
+			assert.Nil(t, chunks)
+
+			var markupErr *MarkupError
+			assert.ErrorAs(t, err, &markupErr)
+		})
+	}
+}
+
+func TestSplitWithEntities(t *testing.T) {
+	text := "aaaa bbbb cccc"
+	entities := []tgbotapi.MessageEntity{
+		{Type: "bold", Offset: 0, Length: 14},
+		{Type: "italic", Offset: 10, Length: 4},
+	}
+
+	chunks, chunkEntities := SplitWithEntities(text, entities, 10)
+	assert.Equal(t, []string{"aaaa bbbb", "cccc"}, chunks)
+	assert.Equal(t, [][]tgbotapi.MessageEntity{
+		{{Type: "bold", Offset: 0, Length: 9}},
+		{{Type: "bold", Offset: 0, Length: 4}, {Type: "italic", Offset: 0, Length: 4}},
+	}, chunkEntities)
+}
+
+func TestSplitWithEntitiesCode(t *testing.T) {
+	// whitespace inside code is kept at the cut
+	chunks, chunkEntities := SplitWithEntities("aaaa bbbb", []tgbotapi.MessageEntity{{Type: "code", Offset: 0, Length: 9}}, 5)
+	assert.Equal(t, []string{"aaaa ", "bbbb"}, chunks)
+	assert.Equal(t, [][]tgbotapi.MessageEntity{
+		{{Type: "code", Offset: 0, Length: 5}},
+		{{Type: "code", Offset: 0, Length: 4}},
+	}, chunkEntities)
+}
+
+func TestSplitWithEntitiesLongMessage(t *testing.T) {
+	word := "bidoof "
+	text := strings.Repeat(word, MAX_MESSAGE_LENGTH)
+
+	// every word bold
+	var entities []tgbotapi.MessageEntity
+	for i := 0; i < MAX_MESSAGE_LENGTH; i++ {
+		entities = append(entities, tgbotapi.MessageEntity{Type: "bold", Offset: i * len(word), Length: len(word) - 1})
+	}
+
+	chunks, chunkEntities := SplitWithEntities(text, entities, MAX_MESSAGE_LENGTH)
+	assert.Greater(t, len(chunks), 1)
+
+	n := 0
+	for i, c := range chunks {
+		assert.LessOrEqual(t, UTF16Len(c), MAX_MESSAGE_LENGTH)
+		for _, e := range chunkEntities[i] {
+			assert.LessOrEqual(t, e.Offset+e.Length, UTF16Len(c))
+		}
+		n += len(chunkEntities[i])
+	}
+	assert.Equal(t, len(entities), n)
+}
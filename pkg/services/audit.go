@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// List audit trail of mutating RPCs, newest first, paginated by `page_size`
+// & `page_token`
+func (se *Services) ListAuditEvents(ctx context.Context, pbIn *telegrampb.ListAuditEventsRequest) (*telegrampb.ListAuditEventsResponse, error) {
+	filter := datasource.AuditEventFilter{
+		Caller: pbIn.GetFilterCaller(),
+		Method: pbIn.GetFilterMethod(),
+		Code:   pbIn.GetFilterCode(),
+	}
+
+	if pbIn.StartTime != nil {
+		filter.From = pbIn.GetStartTime().AsTime()
+	}
+
+	if pbIn.EndTime != nil {
+		filter.To = pbIn.GetEndTime().AsTime()
+	}
+
+	// check pagination
+	limit := pageSize(pbIn.GetPageSize())
+	beforeId, err := decodePageToken(pbIn.GetPageToken())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("page_token", pbIn.GetPageToken()).Msg("rpc.ListAuditEvents.pageToken")
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+
+	// fetch one extra row to know whether there is a next page
+	res, err := se.DataSource.GetAuditEvents(ctx, filter, beforeId, limit+1)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.ListAuditEvents.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	pbOut := &telegrampb.ListAuditEventsResponse{
+		Events: []*telegrampb.AuditEvent{},
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+		pbOut.NextPageToken = encodePageToken(res[limit-1].ID)
+	}
+
+	for i := range res {
+		pbOut.Events = append(pbOut.Events, &telegrampb.AuditEvent{
+			Id:        res[i].ID,
+			RequestId: res[i].RequestID,
+			Caller:    res[i].Caller,
+			Peer:      res[i].Peer,
+			Method:    res[i].Method,
+			Params:    res[i].Params,
+			Code:      res[i].Code,
+			Error:     res[i].Error,
+			CreatedAt: timestamppb.New(res[i].CreatedAt),
+		})
+	}
+
+	return pbOut, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Ban user from the bot, same as /ban in chat
+func (se *Services) BanUser(ctx context.Context, pbIn *telegrampb.BanUserRequest) (*telegrampb.BanUserResponse, error) {
+	if pbIn.GetChatId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Chat ID of a user is required")
+	}
+
+	isAdmin, err := se.DataSource.HasChatRole(ctx, pbIn.GetChatId(), datasource.ROLE_ADMIN)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.BanUser.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+	if isAdmin {
+		return nil, status.Error(codes.FailedPrecondition, "Admins can't be banned")
+	}
+
+	ban := &datasource.ChatBan{
+		ChatID: pbIn.GetChatId(),
+		Reason: pbIn.GetReason(),
+	}
+	if err := se.DataSource.BanChat(ctx, ban); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.BanUser.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	res, err := se.DataSource.GetChatBan(ctx, pbIn.GetChatId())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.BanUser.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	log.Ctx(ctx).Info().Int64("target", pbIn.GetChatId()).Msg("rpc.BanUser.result")
+
+	return &telegrampb.BanUserResponse{Ban: chatBanToPb(res)}, nil
+}
+
+func (se *Services) UnbanUser(ctx context.Context, pbIn *telegrampb.UnbanUserRequest) (*telegrampb.UnbanUserResponse, error) {
+	if pbIn.GetChatId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Chat ID of a user is required")
+	}
+
+	unbanned, err := se.DataSource.UnbanChat(ctx, pbIn.GetChatId())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.UnbanUser.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	log.Ctx(ctx).Info().Int64("target", pbIn.GetChatId()).Bool("unbanned", unbanned).Msg("rpc.UnbanUser.result")
+
+	return &telegrampb.UnbanUserResponse{Unbanned: unbanned}, nil
+}
+
+// List banned users, paginated by `page_size` & `page_token`
+func (se *Services) ListBans(ctx context.Context, pbIn *telegrampb.ListBansRequest) (*telegrampb.ListBansResponse, error) {
+	limit := pageSize(pbIn.GetPageSize())
+	afterChatId, err := decodePageToken(pbIn.GetPageToken())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("page_token", pbIn.GetPageToken()).Msg("rpc.ListBans.pageToken")
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+
+	// fetch one extra row to know whether there is a next page
+	res, err := se.DataSource.GetChatBans(ctx, afterChatId, limit+1)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.ListBans.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	pbOut := &telegrampb.ListBansResponse{
+		Bans: []*telegrampb.ChatBan{},
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+		pbOut.NextPageToken = encodePageToken(res[limit-1].ChatID)
+	}
+
+	for i := range res {
+		pbOut.Bans = append(pbOut.Bans, chatBanToPb(&res[i]))
+	}
+
+	return pbOut, nil
+}
+
+func chatBanToPb(ban *datasource.ChatBan) *telegrampb.ChatBan {
+	return &telegrampb.ChatBan{
+		ChatId:    ban.ChatID,
+		Reason:    ban.Reason,
+		BannedBy:  ban.BannedBy,
+		CreatedAt: timestamppb.New(ban.CreatedAt),
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reload config of this gRPC controller now instead of waiting for the file
+// watcher, reports what was applied & what needs a restart. The bot process
+// isn't reached: keys only it uses are listed separately, and it picks up
+// changes only when it reloads itself (file change or SIGHUP).
+func (se *Services) ReloadControllerConfig(ctx context.Context, pbIn *telegrampb.ReloadControllerConfigRequest) (*telegrampb.ReloadControllerConfigResponse, error) {
+	res, err := se.DataSource.Config.Reload()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.ReloadControllerConfig.result")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	log.Ctx(ctx).Info().Strs("applied", res.Applied).Strs("restart_required", res.RestartRequired).Strs("bot_only", res.Ignored).Msg("rpc.ReloadControllerConfig.result")
+
+	return &telegrampb.ReloadControllerConfigResponse{
+		AppliedKeys:         res.Applied,
+		RestartRequiredKeys: res.RestartRequired,
+		BotOnlyKeys:         res.Ignored,
+		BotReloadRequired:   len(res.Applied)+len(res.RestartRequired)+len(res.Ignored) != 0,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/telegram"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+)
+
+// entity types accepted from clients, the rest are detected by Telegram
+// itself (mention, hashtag, url, ...)
+var allowedEntityTypes = map[string]bool{
+	"bold":          true,
+	"italic":        true,
+	"underline":     true,
+	"strikethrough": true,
+	"spoiler":       true,
+	"code":          true,
+	"pre":           true,
+	"text_link":     true,
+}
+
+// resolve parse mode, unspecified falls back to the deprecated `use_markdown`
+func parseModeFromPb(pbIn *telegrampb.SendMessageRequest) (render.ParseMode, error) {
+	if pbIn.GetParseMode() == telegrampb.ParseMode_PARSE_MODE_UNSPECIFIED && pbIn.GetUseMarkdown() {
+		return render.PARSE_MODE_MARKDOWN_V2, nil
+	}
+
+	return parseModeFromEnum(pbIn.GetParseMode())
+}
+
+func parseModeFromEnum(parseMode telegrampb.ParseMode) (render.ParseMode, error) {
+	switch parseMode {
+	case telegrampb.ParseMode_PARSE_MODE_UNSPECIFIED, telegrampb.ParseMode_PARSE_MODE_PLAIN:
+		return render.PARSE_MODE_PLAIN, nil
+	case telegrampb.ParseMode_PARSE_MODE_MARKDOWN_V2:
+		return render.PARSE_MODE_MARKDOWN_V2, nil
+	case telegrampb.ParseMode_PARSE_MODE_HTML:
+		return render.PARSE_MODE_HTML, nil
+	default:
+		return render.PARSE_MODE_PLAIN, fmt.Errorf("unknown parse mode %v", parseMode)
+	}
+}
+
+// validate client entities against `text`, Telegram would otherwise reject
+// the whole message with a vague error
+func entitiesFromPb(text string, parseMode render.ParseMode, pbEntities []*telegrampb.MessageEntity) ([]tgbotapi.MessageEntity, error) {
+	if len(pbEntities) == 0 {
+		return nil, nil
+	}
+
+	if parseMode != render.PARSE_MODE_PLAIN {
+		return nil, fmt.Errorf("entities can only be used with plain parse mode")
+	}
+
+	textLen := render.UTF16Len(text)
+	entities := make([]tgbotapi.MessageEntity, 0, len(pbEntities))
+	for i, e := range pbEntities {
+		switch {
+		case !allowedEntityTypes[e.GetType()]:
+			return nil, fmt.Errorf("entities[%d]: unsupported type %q", i, e.GetType())
+		case e.GetOffset() < 0 || e.GetLength() <= 0:
+			return nil, fmt.Errorf("entities[%d]: offset must be >= 0 and length must be > 0", i)
+		case int(e.GetOffset())+int(e.GetLength()) > textLen:
+			return nil, fmt.Errorf("entities[%d]: out of text bounds (%d UTF-16 code units)", i, textLen)
+		case e.GetType() == "text_link" && len(e.GetUrl()) == 0:
+			return nil, fmt.Errorf("entities[%d]: text_link requires url", i)
+		}
+
+		entities = append(entities, tgbotapi.MessageEntity{
+			Type:     e.GetType(),
+			Offset:   int(e.GetOffset()),
+			Length:   int(e.GetLength()),
+			URL:      e.GetUrl(),
+			Language: e.GetLanguage(),
+		})
+	}
+
+	return entities, nil
+}
+
+// validate & sanitize media caption the same way as message text
+func captionFromPb(pbCaption *telegrampb.Caption) (telegram.Caption, error) {
+	text, parseMode, entities, err := formatFromPb(pbCaption.GetText(), pbCaption.GetParseMode(), pbCaption.GetEntities(), pbCaption.GetSanitizeMode(), render.MAX_CAPTION_LENGTH)
+	if err != nil {
+		return telegram.Caption{}, err
+	}
+
+	return telegram.Caption{Text: text, ParseMode: parseMode, Entities: entities}, nil
+}
+
+// validate & sanitize text that can't be split, so it must not be longer than
+// `maxLength` once formatted
+func formatFromPb(text string, pbParseMode telegrampb.ParseMode, pbEntities []*telegrampb.MessageEntity, sanitizeMode telegrampb.SanitizeMode, maxLength int) (string, render.ParseMode, []tgbotapi.MessageEntity, error) {
+	parseMode, err := parseModeFromEnum(pbParseMode)
+	if err != nil {
+		return "", parseMode, nil, err
+	}
+
+	entities, err := entitiesFromPb(text, parseMode, pbEntities)
+	if err != nil {
+		return "", parseMode, nil, err
+	}
+
+	text, parseMode, err = sanitize(text, parseMode, sanitizeMode)
+	if err != nil {
+		return "", parseMode, nil, err
+	}
+
+	if n := render.UTF16Len(render.StripMarkup(parseMode, text)); n > maxLength {
+		return "", parseMode, nil, fmt.Errorf("text is %d characters long, at most %d allowed", n, maxLength)
+	}
+
+	return text, parseMode, entities, nil
+}
+
+// sanitize markup in `text` according to `mode`, returns the text & parse
+// mode to send it with. Malformed markup is only an error with no sanitizing,
+// the other modes always produce something Telegram accepts.
+func sanitize(text string, parseMode render.ParseMode, mode telegrampb.SanitizeMode) (string, render.ParseMode, error) {
+	switch mode {
+	case telegrampb.SanitizeMode_SANITIZE_MODE_UNSPECIFIED, telegrampb.SanitizeMode_SANITIZE_MODE_NONE:
+		return text, parseMode, render.ValidateMarkup(parseMode, text)
+	case telegrampb.SanitizeMode_SANITIZE_MODE_ESCAPE:
+		return render.EscapeMarkup(parseMode, text), parseMode, nil
+	case telegrampb.SanitizeMode_SANITIZE_MODE_STRIP:
+		return render.StripMarkup(parseMode, text), render.PARSE_MODE_PLAIN, nil
+	default:
+		return text, parseMode, fmt.Errorf("unknown sanitize mode %v", mode)
+	}
+}
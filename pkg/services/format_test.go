@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+)
+
+func TestParseModeFromPb(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		In     *telegrampb.SendMessageRequest
+		Expect render.ParseMode
+	}{
+		{"unspecified", &telegrampb.SendMessageRequest{}, render.PARSE_MODE_PLAIN},
+		{"use_markdown", &telegrampb.SendMessageRequest{UseMarkdown: true}, render.PARSE_MODE_MARKDOWN_V2},
+		{"html", &telegrampb.SendMessageRequest{ParseMode: telegrampb.ParseMode_PARSE_MODE_HTML, UseMarkdown: true}, render.PARSE_MODE_HTML},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			parseMode, err := parseModeFromPb(tc.In)
+			if assert.Nil(t, err) {
+				assert.Equal(t, tc.Expect, parseMode)
+			}
+		})
+	}
+}
+
+func TestEntitiesFromPb(t *testing.T) {
+	text := "🦫 Bidoof"
+
+	entities, err := entitiesFromPb(text, render.PARSE_MODE_PLAIN, []*telegrampb.MessageEntity{
+		{Type: "bold", Offset: 3, Length: 6},
+	})
+	if assert.Nil(t, err) {
+		assert.Len(t, entities, 1)
+	}
+
+	invalid := map[string]*telegrampb.MessageEntity{
+		"out_of_bounds":    {Type: "bold", Offset: 3, Length: 7},
+		"negative_offset":  {Type: "bold", Offset: -1, Length: 1},
+		"unsupported_type": {Type: "mention", Offset: 0, Length: 1},
+		"link_without_url": {Type: "text_link", Offset: 0, Length: 1},
+	}
+	for name, e := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := entitiesFromPb(text, render.PARSE_MODE_PLAIN, []*telegrampb.MessageEntity{e})
+			assert.NotNil(t, err)
+		})
+	}
+
+	t.Run("with_parse_mode", func(t *testing.T) {
+		_, err := entitiesFromPb(text, render.PARSE_MODE_HTML, []*telegrampb.MessageEntity{{Type: "bold", Offset: 0, Length: 1}})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	text := "Hello *world*. (hi)"
+
+	_, _, err := sanitize(text, render.PARSE_MODE_MARKDOWN_V2, telegrampb.SanitizeMode_SANITIZE_MODE_UNSPECIFIED)
+	assert.NotNil(t, err)
+
+	// plain text never needs sanitizing
+	msg, _, err := sanitize(text, render.PARSE_MODE_PLAIN, telegrampb.SanitizeMode_SANITIZE_MODE_NONE)
+	if assert.Nil(t, err) {
+		assert.Equal(t, text, msg)
+	}
+
+	msg, parseMode, err := sanitize(text, render.PARSE_MODE_MARKDOWN_V2, telegrampb.SanitizeMode_SANITIZE_MODE_ESCAPE)
+	if assert.Nil(t, err) {
+		assert.Equal(t, `Hello *world*\. \(hi\)`, msg)
+		assert.Equal(t, render.PARSE_MODE_MARKDOWN_V2, parseMode)
+	}
+
+	msg, parseMode, err = sanitize(text, render.PARSE_MODE_MARKDOWN_V2, telegrampb.SanitizeMode_SANITIZE_MODE_STRIP)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Hello world. (hi)", msg)
+		assert.Equal(t, render.PARSE_MODE_PLAIN, parseMode)
+	}
+}
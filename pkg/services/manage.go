@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/telegram"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Replace text of a message sent by the bot, e.g. from SendMessage
+func (se *Services) EditMessageText(ctx context.Context, pbIn *telegrampb.EditMessageTextRequest) (*telegrampb.EditMessageTextResponse, error) {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	if pbIn.GetMessageId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Message ID is required")
+	}
+
+	text, parseMode, entities, err := formatFromPb(pbIn.GetText(), pbIn.GetParseMode(), pbIn.GetEntities(), pbIn.GetSanitizeMode(), render.MAX_MESSAGE_LENGTH)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.EditMessageText.format")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := t.EditMessageText(ctx, pbIn.GetChatId(), int(pbIn.GetMessageId()), text, parseMode, entities); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.EditMessageText.result")
+		return nil, err
+	}
+
+	return &telegrampb.EditMessageTextResponse{
+		MessageId: pbIn.GetMessageId(),
+		ChatId:    pbIn.GetChatId(),
+	}, nil
+}
+
+func (se *Services) DeleteMessage(ctx context.Context, pbIn *telegrampb.DeleteMessageRequest) (*telegrampb.DeleteMessageResponse, error) {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	if pbIn.GetMessageId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Message ID is required")
+	}
+
+	if err := t.DeleteMessage(ctx, pbIn.GetChatId(), int(pbIn.GetMessageId())); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.DeleteMessage.result")
+		return nil, err
+	}
+
+	return &telegrampb.DeleteMessageResponse{}, nil
+}
+
+func (se *Services) PinMessage(ctx context.Context, pbIn *telegrampb.PinMessageRequest) (*telegrampb.PinMessageResponse, error) {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	if pbIn.GetMessageId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Message ID is required")
+	}
+
+	if err := t.PinMessage(ctx, pbIn.GetChatId(), int(pbIn.GetMessageId()), pbIn.GetDisableNotification()); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.PinMessage.result")
+		return nil, err
+	}
+
+	return &telegrampb.PinMessageResponse{}, nil
+}
+
+func (se *Services) UnpinMessage(ctx context.Context, pbIn *telegrampb.UnpinMessageRequest) (*telegrampb.UnpinMessageResponse, error) {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	if pbIn.GetMessageId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid message ID")
+	}
+
+	if err := t.UnpinMessage(ctx, pbIn.GetChatId(), int(pbIn.GetMessageId())); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.UnpinMessage.result")
+		return nil, err
+	}
+
+	return &telegrampb.UnpinMessageResponse{}, nil
+}
+
+func (se *Services) ForwardMessage(ctx context.Context, pbIn *telegrampb.ForwardMessageRequest) (*telegrampb.ForwardMessageResponse, error) {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	if pbIn.GetMessageId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Message ID is required")
+	}
+
+	messageId, err := t.ForwardMessage(ctx, pbIn.GetChatId(), pbIn.GetFromChatId(), int(pbIn.GetMessageId()), pbIn.GetDisableNotification())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.ForwardMessage.result")
+		return nil, err
+	}
+
+	return &telegrampb.ForwardMessageResponse{
+		MessageId: messageId,
+		ChatId:    pbIn.GetChatId(),
+	}, nil
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/telegram"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// upload limits of the Bot API
+const (
+	MAX_PHOTO_UPLOAD_SIZE = 10 << 20
+	MAX_FILE_UPLOAD_SIZE  = 50 << 20
+)
+
+// uploads are buffered in memory until sent, this bounds what one call can
+// hold (an album could otherwise take 10 files of 50 MB)
+const MAX_UPLOAD_TOTAL_SIZE = 50 << 20
+
+// album size limits of the Bot API
+const (
+	MIN_MEDIA_GROUP_SIZE = 2
+	MAX_MEDIA_GROUP_SIZE = 10
+)
+
+// request of a client-streaming media RPC, header first then file chunks
+type mediaRequest interface {
+	GetChunk() *telegrampb.FileChunk
+}
+
+type mediaFile struct {
+	input   *telegrampb.InputFile
+	maxSize int
+}
+
+func (se *Services) SendPhoto(stream telegrampb.TelegramService_SendPhotoServer) error {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	chatId, res, err := sendSingleMedia(stream.Context(), "SendPhoto", stream.Recv, func(req *telegrampb.SendPhotoRequest) (*singleMedia, error) {
+		header := req.GetHeader()
+		if header == nil {
+			return nil, status.Error(codes.InvalidArgument, "First message must be the header")
+		}
+
+		caption, err := captionFromPb(header.GetCaption())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return &singleMedia{header.GetChatId(), mediaFile{header.GetPhoto(), MAX_PHOTO_UPLOAD_SIZE}, func(ctx context.Context, file tgbotapi.RequestFileData) (*telegram.RespSendMedia, error) {
+			return t.SendPhoto(ctx, header.GetChatId(), file, caption)
+		}}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&telegrampb.SendPhotoResponse{
+		MessageId: res.MessageID,
+		ChatId:    chatId,
+		FileId:    res.FileID,
+	})
+}
+
+func (se *Services) SendDocument(stream telegrampb.TelegramService_SendDocumentServer) error {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	chatId, res, err := sendSingleMedia(stream.Context(), "SendDocument", stream.Recv, func(req *telegrampb.SendDocumentRequest) (*singleMedia, error) {
+		header := req.GetHeader()
+		if header == nil {
+			return nil, status.Error(codes.InvalidArgument, "First message must be the header")
+		}
+
+		caption, err := captionFromPb(header.GetCaption())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return &singleMedia{header.GetChatId(), mediaFile{header.GetDocument(), MAX_FILE_UPLOAD_SIZE}, func(ctx context.Context, file tgbotapi.RequestFileData) (*telegram.RespSendMedia, error) {
+			return t.SendDocument(ctx, header.GetChatId(), file, caption)
+		}}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&telegrampb.SendDocumentResponse{
+		MessageId: res.MessageID,
+		ChatId:    chatId,
+		FileId:    res.FileID,
+	})
+}
+
+func (se *Services) SendSticker(stream telegrampb.TelegramService_SendStickerServer) error {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	chatId, res, err := sendSingleMedia(stream.Context(), "SendSticker", stream.Recv, func(req *telegrampb.SendStickerRequest) (*singleMedia, error) {
+		header := req.GetHeader()
+		if header == nil {
+			return nil, status.Error(codes.InvalidArgument, "First message must be the header")
+		}
+
+		return &singleMedia{header.GetChatId(), mediaFile{header.GetSticker(), MAX_FILE_UPLOAD_SIZE}, func(ctx context.Context, file tgbotapi.RequestFileData) (*telegram.RespSendMedia, error) {
+			return t.SendSticker(ctx, header.GetChatId(), file)
+		}}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&telegrampb.SendStickerResponse{
+		MessageId: res.MessageID,
+		ChatId:    chatId,
+		FileId:    res.FileID,
+	})
+}
+
+func (se *Services) SendMediaGroup(stream telegrampb.TelegramService_SendMediaGroupServer) error {
+	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "First message must be the header")
+	}
+
+	pbMedia := header.GetMedia()
+	if len(pbMedia) < MIN_MEDIA_GROUP_SIZE || len(pbMedia) > MAX_MEDIA_GROUP_SIZE {
+		return status.Errorf(codes.InvalidArgument, "Media group must have %d-%d items", MIN_MEDIA_GROUP_SIZE, MAX_MEDIA_GROUP_SIZE)
+	}
+
+	// validate everything before receiving the uploads
+	captions := make([]telegram.Caption, len(pbMedia))
+	mediaFiles := make([]mediaFile, len(pbMedia))
+	for i, m := range pbMedia {
+		switch {
+		case m.GetType() == telegrampb.MediaType_MEDIA_TYPE_UNSPECIFIED:
+			return status.Errorf(codes.InvalidArgument, "media[%d]: type is required", i)
+		case m.GetType() != pbMedia[0].GetType() && (m.GetType() == telegrampb.MediaType_MEDIA_TYPE_DOCUMENT || pbMedia[0].GetType() == telegrampb.MediaType_MEDIA_TYPE_DOCUMENT):
+			return status.Error(codes.InvalidArgument, "Documents can't be mixed with other media types")
+		}
+
+		captions[i], err = captionFromPb(m.GetCaption())
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "media[%d]: %s", i, err)
+		}
+
+		mediaFiles[i] = mediaFile{m.GetFile(), MAX_FILE_UPLOAD_SIZE}
+		if m.GetType() == telegrampb.MediaType_MEDIA_TYPE_PHOTO {
+			mediaFiles[i].maxSize = MAX_PHOTO_UPLOAD_SIZE
+		}
+	}
+
+	files, err := receiveFiles(stream.Recv, mediaFiles)
+	if err != nil {
+		log.Ctx(stream.Context()).Error().Err(err).Msg("rpc.SendMediaGroup.upload")
+		return err
+	}
+
+	media := make([]any, len(pbMedia))
+	for i, m := range pbMedia {
+		base := tgbotapi.BaseInputMedia{
+			Media:           files[i],
+			Caption:         captions[i].Text,
+			ParseMode:       string(captions[i].ParseMode),
+			CaptionEntities: captions[i].Entities,
+		}
+
+		switch m.GetType() {
+		case telegrampb.MediaType_MEDIA_TYPE_PHOTO:
+			base.Type = "photo"
+			media[i] = tgbotapi.InputMediaPhoto{BaseInputMedia: base}
+		default:
+			base.Type = "document"
+			media[i] = tgbotapi.InputMediaDocument{BaseInputMedia: base}
+		}
+	}
+
+	res, err := t.SendMediaGroup(stream.Context(), header.GetChatId(), media)
+	if err != nil {
+		log.Ctx(stream.Context()).Error().Err(err).Msg("rpc.SendMediaGroup.result")
+		return err
+	}
+
+	return stream.SendAndClose(&telegrampb.SendMediaGroupResponse{
+		MessageIds: res.MessageIDs,
+		ChatId:     header.GetChatId(),
+		FileIds:    res.FileIDs,
+	})
+}
+
+// what a single-file media RPC sends, described by its header
+type singleMedia struct {
+	chatId int64
+	file   mediaFile
+
+	// send the received file
+	send func(ctx context.Context, file tgbotapi.RequestFileData) (*telegram.RespSendMedia, error)
+}
+
+// Shared flow of single-file media RPCs: `fromHeader` validates the first
+// request, which must be the header, then the file is received & sent
+func sendSingleMedia[R mediaRequest](ctx context.Context, rpc string, recv func() (R, error), fromHeader func(R) (*singleMedia, error)) (chatId int64, res *telegram.RespSendMedia, err error) {
+	first, err := recv()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	media, err := fromHeader(first)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	files, err := receiveFiles(recv, []mediaFile{media.file})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc." + rpc + ".upload")
+		return 0, nil, err
+	}
+
+	res, err = media.send(ctx, files[0])
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc." + rpc + ".result")
+		return 0, nil, err
+	}
+
+	return media.chatId, res, nil
+}
+
+// resolve every file to send, reading chunks of uploaded ones from the stream
+// until the client closes it
+func receiveFiles[R mediaRequest](recv func() (R, error), mediaFiles []mediaFile) ([]tgbotapi.RequestFileData, error) {
+	files := make([]tgbotapi.RequestFileData, len(mediaFiles))
+	uploads := make(map[int32]*bytes.Buffer)
+	total := 0
+
+	for i, f := range mediaFiles {
+		switch src := f.input.GetSource().(type) {
+		case *telegrampb.InputFile_Url:
+			if len(src.Url) != 0 {
+				files[i] = tgbotapi.FileURL(src.Url)
+				continue
+			}
+		case *telegrampb.InputFile_FileId:
+			if len(src.FileId) != 0 {
+				files[i] = tgbotapi.FileID(src.FileId)
+				continue
+			}
+		case *telegrampb.InputFile_UploadName:
+			if len(src.UploadName) != 0 {
+				uploads[int32(i)] = new(bytes.Buffer)
+				continue
+			}
+		}
+
+		return nil, status.Errorf(codes.InvalidArgument, "media[%d]: file url, file ID or upload name is required", i)
+	}
+
+	for {
+		req, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		chunk := req.GetChunk()
+		if chunk == nil {
+			return nil, status.Error(codes.InvalidArgument, "Header must only be sent once")
+		}
+
+		i := chunk.GetMediaIndex()
+		buf, ok := uploads[i]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "media[%d] is not uploaded", i)
+		}
+
+		if buf.Len()+len(chunk.GetData()) > mediaFiles[i].maxSize {
+			return nil, status.Errorf(codes.InvalidArgument, "media[%d]: file exceeds %d MB", i, mediaFiles[i].maxSize>>20)
+		}
+
+		total += len(chunk.GetData())
+		if total > MAX_UPLOAD_TOTAL_SIZE {
+			return nil, status.Errorf(codes.InvalidArgument, "Uploaded files exceed %d MB in total", MAX_UPLOAD_TOTAL_SIZE>>20)
+		}
+		buf.Write(chunk.GetData())
+	}
+
+	for i, buf := range uploads {
+		if buf.Len() == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "media[%d]: uploaded file is empty", i)
+		}
+
+		files[i] = tgbotapi.FileBytes{Name: mediaFiles[i].input.GetUploadName(), Bytes: buf.Bytes()}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/telegram"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fake client stream, returns `chunks` then io.EOF
+func chunkStream(chunks ...*telegrampb.FileChunk) func() (*telegrampb.SendMediaGroupRequest, error) {
+	return func() (*telegrampb.SendMediaGroupRequest, error) {
+		if len(chunks) == 0 {
+			return nil, io.EOF
+		}
+
+		req := &telegrampb.SendMediaGroupRequest{Payload: &telegrampb.SendMediaGroupRequest_Chunk{Chunk: chunks[0]}}
+		chunks = chunks[1:]
+
+		return req, nil
+	}
+}
+
+func TestReceiveFiles(t *testing.T) {
+	mediaFiles := []mediaFile{
+		{&telegrampb.InputFile{Source: &telegrampb.InputFile_Url{Url: "https://example.com/a.jpg"}}, MAX_PHOTO_UPLOAD_SIZE},
+		{&telegrampb.InputFile{Source: &telegrampb.InputFile_UploadName{UploadName: "b.jpg"}}, 4},
+	}
+
+	files, err := receiveFiles(chunkStream(
+		&telegrampb.FileChunk{MediaIndex: 1, Data: []byte("ab")},
+		&telegrampb.FileChunk{MediaIndex: 1, Data: []byte("cd")},
+	), mediaFiles)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []tgbotapi.RequestFileData{
+			tgbotapi.FileURL("https://example.com/a.jpg"),
+			tgbotapi.FileBytes{Name: "b.jpg", Bytes: []byte("abcd")},
+		}, files)
+	}
+
+	testCases := []struct {
+		Name   string
+		Chunks []*telegrampb.FileChunk
+	}{
+		{"not_uploaded", []*telegrampb.FileChunk{{MediaIndex: 0, Data: []byte("ab")}}},
+		{"too_large", []*telegrampb.FileChunk{{MediaIndex: 1, Data: []byte("abc")}, {MediaIndex: 1, Data: []byte("de")}}},
+		{"empty", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := receiveFiles(chunkStream(tc.Chunks...), mediaFiles)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestReceiveFilesTotalSize(t *testing.T) {
+	upload := &telegrampb.InputFile{Source: &telegrampb.InputFile_UploadName{UploadName: "a.pdf"}}
+	mediaFiles := []mediaFile{{upload, MAX_FILE_UPLOAD_SIZE}, {upload, MAX_FILE_UPLOAD_SIZE}}
+
+	// each file is under its limit, both together are not
+	data := make([]byte, MAX_UPLOAD_TOTAL_SIZE/2+1)
+	_, err := receiveFiles(chunkStream(
+		&telegrampb.FileChunk{MediaIndex: 0, Data: data},
+		&telegrampb.FileChunk{MediaIndex: 1, Data: data},
+	), mediaFiles)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSendSingleMedia(t *testing.T) {
+	recv := func(reqs ...*telegrampb.SendStickerRequest) func() (*telegrampb.SendStickerRequest, error) {
+		return func() (*telegrampb.SendStickerRequest, error) {
+			if len(reqs) == 0 {
+				return nil, io.EOF
+			}
+
+			req := reqs[0]
+			reqs = reqs[1:]
+
+			return req, nil
+		}
+	}
+
+	fromHeader := func(req *telegrampb.SendStickerRequest) (*singleMedia, error) {
+		header := req.GetHeader()
+		if header == nil {
+			return nil, status.Error(codes.InvalidArgument, "First message must be the header")
+		}
+
+		return &singleMedia{header.GetChatId(), mediaFile{header.GetSticker(), MAX_FILE_UPLOAD_SIZE}, func(ctx context.Context, file tgbotapi.RequestFileData) (*telegram.RespSendMedia, error) {
+			assert.Equal(t, tgbotapi.FileBytes{Name: "a.webp", Bytes: []byte("ab")}, file)
+			return &telegram.RespSendMedia{MessageID: 1, FileID: "file"}, nil
+		}}, nil
+	}
+
+	header := &telegrampb.SendStickerRequest{Payload: &telegrampb.SendStickerRequest_Header{Header: &telegrampb.StickerHeader{
+		ChatId:  1234,
+		Sticker: &telegrampb.InputFile{Source: &telegrampb.InputFile_UploadName{UploadName: "a.webp"}},
+	}}}
+	chunk := &telegrampb.SendStickerRequest{Payload: &telegrampb.SendStickerRequest_Chunk{Chunk: &telegrampb.FileChunk{Data: []byte("ab")}}}
+
+	chatId, res, err := sendSingleMedia(context.Background(), "SendSticker", recv(header, chunk), fromHeader)
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(1234), chatId)
+		assert.Equal(t, "file", res.FileID)
+	}
+
+	_, _, err = sendSingleMedia(context.Background(), "SendSticker", recv(chunk), fromHeader)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
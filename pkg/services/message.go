@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var messageDirectionToPb = map[string]telegrampb.MessageDirection{
+	datasource.MESSAGE_INBOUND:  telegrampb.MessageDirection_MESSAGE_DIRECTION_INBOUND,
+	datasource.MESSAGE_OUTBOUND: telegrampb.MessageDirection_MESSAGE_DIRECTION_OUTBOUND,
+}
+
+// Search message history, newest first, paginated by `page_size` &
+// `page_token`
+func (se *Services) SearchMessages(ctx context.Context, pbIn *telegrampb.SearchMessagesRequest) (*telegrampb.SearchMessagesResponse, error) {
+	filter := datasource.MessageFilter{
+		ChatID:  pbIn.GetFilterChatId(),
+		Command: pbIn.GetFilterCommand(),
+		Text:    pbIn.GetFilterText(),
+	}
+
+	if pbIn.StartTime != nil {
+		filter.From = pbIn.GetStartTime().AsTime()
+	}
+
+	if pbIn.EndTime != nil {
+		filter.To = pbIn.GetEndTime().AsTime()
+	}
+
+	switch pbIn.GetFilterDirection() {
+	case telegrampb.MessageDirection_MESSAGE_DIRECTION_UNSPECIFIED:
+	case telegrampb.MessageDirection_MESSAGE_DIRECTION_INBOUND:
+		filter.Direction = datasource.MESSAGE_INBOUND
+	case telegrampb.MessageDirection_MESSAGE_DIRECTION_OUTBOUND:
+		filter.Direction = datasource.MESSAGE_OUTBOUND
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Unknown message direction")
+	}
+
+	// check pagination
+	limit := pageSize(pbIn.GetPageSize())
+	beforeId, err := decodePageToken(pbIn.GetPageToken())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("page_token", pbIn.GetPageToken()).Msg("rpc.SearchMessages.pageToken")
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+
+	// fetch one extra row to know whether there is a next page
+	res, err := se.DataSource.SearchMessages(ctx, filter, beforeId, limit+1)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.SearchMessages.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	pbOut := &telegrampb.SearchMessagesResponse{
+		Messages: []*telegrampb.HistoryMessage{},
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+		pbOut.NextPageToken = encodePageToken(res[limit-1].ID)
+	}
+
+	for i := range res {
+		pbOut.Messages = append(pbOut.Messages, &telegrampb.HistoryMessage{
+			Id:        res[i].ID,
+			ChatId:    res[i].ChatID,
+			Direction: messageDirectionToPb[res[i].Direction],
+			MessageId: res[i].MessageID,
+			Command:   res[i].Command,
+			Text:      res[i].Text,
+			SentAt:    timestamppb.New(res[i].SentAt),
+			CreatedAt: timestamppb.New(res[i].CreatedAt),
+		})
+	}
+
+	return pbOut, nil
+}
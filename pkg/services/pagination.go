@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+const (
+	// used when the client doesn't specify page size
+	DEFAULT_PAGE_SIZE = 50
+
+	// page size requested above this will be capped
+	MAX_PAGE_SIZE = 500
+
+	// number of rows fetched from database per batch when streaming
+	STREAM_BATCH_SIZE = 200
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// clamp requested page size to the allowed range
+func pageSize(requested uint32) int {
+	switch {
+	case requested == 0:
+		return DEFAULT_PAGE_SIZE
+	case requested > MAX_PAGE_SIZE:
+		return MAX_PAGE_SIZE
+	default:
+		return int(requested)
+	}
+}
+
+// page token is an opaque string for the client, but internally it is just
+// the last ID (e.g. chat_id) of the previous page
+func encodePageToken(lastId int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastId, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if len(token) == 0 {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+
+	lastId, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+
+	return lastId, nil
+}
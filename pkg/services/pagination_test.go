@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageSize(t *testing.T) {
+	assert.Equal(t, DEFAULT_PAGE_SIZE, pageSize(0))
+	assert.Equal(t, 10, pageSize(10))
+	assert.Equal(t, MAX_PAGE_SIZE, pageSize(MAX_PAGE_SIZE+1))
+}
+
+func TestPageToken(t *testing.T) {
+	t.Run("round_trip", func(t *testing.T) {
+		lastChatId, err := decodePageToken(encodePageToken(1900131050))
+		if assert.Nil(t, err) {
+			assert.Equal(t, int64(1900131050), lastChatId)
+		}
+	})
+
+	t.Run("empty_is_first_page", func(t *testing.T) {
+		lastChatId, err := decodePageToken("")
+		if assert.Nil(t, err) {
+			assert.Equal(t, int64(0), lastChatId)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, token := range []string{"!!!", "YWJj"} {
+			_, err := decodePageToken(token)
+			assert.ErrorIs(t, err, ErrInvalidPageToken)
+		}
+	})
+}
@@ -2,12 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/async"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/telegram"
@@ -17,12 +18,9 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// for handling special characters, list them here. It will be replaced with
-// empty character
-const SPECIAL_CHARACTERS = ".{}[]!?"
-
 type Services struct {
 	BotAPI     *tgbotapi.BotAPI
 	GrpcServer *grpc.Server
@@ -36,7 +34,7 @@ func NewServices(g *grpc.Server, ds *datasource.DataSource, bot *tgbotapi.BotAPI
 func (se *Services) InitServices() {
 	telegrampb.RegisterTelegramServiceServer(se.GrpcServer, se)
 
-	if se.DataSource.Config.Grpc.Mode != "production" {
+	if se.DataSource.Config.Get().Grpc.Mode != "production" {
 		reflection.Register(se.GrpcServer)
 		fmt.Println("REFLECTION ENABLED")
 		debug.DebugStruct(se.GrpcServer.GetServiceInfo())
@@ -47,7 +45,7 @@ func (se *Services) BotStatus(ctx context.Context, pbIn *telegrampb.BotStatusReq
 	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
 
 	if resp, err := t.GetBotStatus(ctx); err != nil {
-		log.Error().Err(err).Msg("rpc.BotStatus.result")
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.BotStatus.result")
 		return nil, err
 	} else {
 		return &telegrampb.BotStatusResponse{
@@ -65,124 +63,184 @@ func (se *Services) BotStatus(ctx context.Context, pbIn *telegrampb.BotStatusReq
 func (se *Services) SendMessage(ctx context.Context, pbIn *telegrampb.SendMessageRequest) (*telegrampb.SendMessageResponse, error) {
 	t := telegram.NewTelegramService(se.DataSource, se.BotAPI)
 
-	// sanitize input, replace special characters with ""
-	var (
-		msg       = pbIn.GetText()
-		strReader = strings.NewReader(SPECIAL_CHARACTERS)
-	)
+	msg := pbIn.GetText()
 
-	for i := 0; i < strReader.Len(); i++ {
-		if b, err := strReader.ReadByte(); err == nil {
-			msg = strings.ReplaceAll(msg, string(b), "")
-		} else {
-			log.Error().Err(err).Msg("rpc.SendMessage.specialCharacter")
-			return nil, status.Error(codes.Internal, "Cannot parse special character list")
-		}
+	parseMode, err := parseModeFromPb(pbIn)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entities, err := entitiesFromPb(msg, parseMode, pbIn.GetEntities())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	msg, parseMode, err = sanitize(msg, parseMode, pbIn.GetSanitizeMode())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.SendMessage.sanitize")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	// send the message
-	if res, err := t.SendChat(ctx, pbIn.GetChatId(), msg, pbIn.GetUseMarkdown()); err != nil {
-		log.Error().Err(err).Msg("rpc.SendMessage.result")
+	if res, err := t.SendChat(ctx, pbIn.GetChatId(), msg, parseMode, entities); err != nil {
+		// the caller can still edit or delete what was delivered
+		if res != nil {
+			err = telegram.WithSentMessages(err, res.MessageIDs)
+			log.Ctx(ctx).Warn().Ints64("sent_message_ids", res.MessageIDs).Msg("rpc.SendMessage.partial")
+		}
+
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.SendMessage.result")
 		return nil, err
 	} else {
 		return &telegrampb.SendMessageResponse{
-			MessageId: res.MessageID,
-			ChatId:    pbIn.GetChatId(),
-			Recipient: res.Recipient,
+			MessageId:  res.MessageID,
+			ChatId:     pbIn.GetChatId(),
+			Recipient:  res.Recipient,
+			MessageIds: res.MessageIDs,
 		}, nil
 	}
 }
 
-// Get list of private chats from database
+// Get list of private chats from database, paginated by `page_size` &
+// `page_token`
 func (se *Services) GetPrivateChat(ctx context.Context, pbIn *telegrampb.GetPrivateChatRequest) (*telegrampb.GetPrivateChatResponse, error) {
-	filter := datasource.NewQueryFilter()
+	filter := privateChatFilter(pbIn.GetFilterChatId(), pbIn.GetFilterUsername())
+
+	// check pagination
+	limit := pageSize(pbIn.GetPageSize())
+	afterChatId, err := decodePageToken(pbIn.GetPageToken())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("page_token", pbIn.GetPageToken()).Msg("rpc.GetPrivateChat.pageToken")
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
 
-	// check query filters
-	if chatId := pbIn.GetFilterChatId(); len(chatId) != 0 {
-		filter["chat_id"] = chatId
+	// fetch one extra row to know whether there is a next page
+	res, err := async.Run(ctx, se.DataSource.Config.Get().Grpc.Timeout.Duration(), func(ctx context.Context) ([]datasource.PrivateChat, error) {
+		return se.DataSource.GetPrivateChatPage(ctx, filter, afterChatId, limit+1)
+	})
+
+	var panicErr *async.PanicError
+	switch {
+	// timeout or somehow this context got canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.GetPrivateChat.timeout")
+		return nil, status.Error(codes.DeadlineExceeded, "RPC timeout")
+
+	case errors.Is(err, context.Canceled):
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.GetPrivateChat.canceled")
+		return nil, status.Error(codes.Canceled, "RPC canceled by server")
+
+	// fatal error happened
+	case errors.As(err, &panicErr):
+		log.Ctx(ctx).Error().Err(err).Bytes("stack", panicErr.Stack).Msg("rpc.GetPrivateChat.FATAL")
+		return nil, status.Error(codes.Internal, "Fatal internal server error")
+
+	// something happened when fetching from database
+	case err != nil:
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.GetPrivateChat.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
 	}
 
-	if username := pbIn.GetFilterUsername(); len(username) != 0 {
-		filter["username"] = username
+	// successful case, empty result is returned as empty list
+	log.Ctx(ctx).Info().Interface("db_result", res).Msg("rpc.GetPrivateChat.result")
+
+	pbOut := &telegrampb.GetPrivateChatResponse{
+		Data: []*telegrampb.ChatData{},
 	}
 
-	// create task context
-	thisCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-	errChan := make(chan error)
-	fatalErr := make(chan error)
-	result := make(chan []datasource.PrivateChat)
+	// there's still more rows after this page
+	if len(res) > limit {
+		res = res[:limit]
+		pbOut.NextPageToken = encodePageToken(res[limit-1].ChatID)
+	}
 
-	// dispatch job to goroutine
-	go func() {
-		defer func() {
-			if err := recover(); err != nil {
-				fatalErr <- fmt.Errorf("%v", err)
-			}
-		}()
-
-		var dbRes []datasource.PrivateChat
-		var err error
-		if len(filter) != 0 {
-			dbRes, err = se.DataSource.GetPrivateChatWithQueryFilter(filter)
-		} else {
-			dbRes, err = se.DataSource.GetPrivateChatWithQueryFilter(nil)
+	// iterate to assign values
+	for i := range res {
+		pbOut.Data = append(pbOut.Data, chatDataFromPrivateChat(&res[i]))
+	}
+	pbOut.Count = uint64(len(res))
+
+	return pbOut, nil
+}
+
+// Stream all private chats matching the filter, fetched from database in
+// batches so the whole table is never loaded into memory at once
+func (se *Services) StreamPrivateChats(pbIn *telegrampb.StreamPrivateChatsRequest, stream telegrampb.TelegramService_StreamPrivateChatsServer) error {
+	ctx := stream.Context()
+	filter := privateChatFilter(pbIn.GetFilterChatId(), pbIn.GetFilterUsername())
+
+	var (
+		afterChatId int64
+		sent        int
+	)
+
+	for {
+		// client went away or the stream deadline exceeded
+		if err := ctx.Err(); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("sent", sent).Msg("rpc.StreamPrivateChats.canceled")
+			return status.FromContextError(err).Err()
 		}
 
+		res, err := se.DataSource.GetPrivateChatPage(ctx, filter, afterChatId, STREAM_BATCH_SIZE)
 		if err != nil {
-			errChan <- err
-		} else {
-			result <- dbRes
+			log.Ctx(ctx).Error().Err(err).Int("sent", sent).Msg("rpc.StreamPrivateChats.database")
+			return status.Error(codes.Internal, "An error occured when querying to database")
 		}
-	}()
 
-	// poll for goroutine result
-	for {
-		select {
-		// timeout or somehow this context got canceled
-		case <-thisCtx.Done():
-			if err := thisCtx.Err(); err == context.DeadlineExceeded {
-				log.Error().Err(err).Msg("rpc.GetPrivateChat.timeout")
-				return nil, status.Error(codes.DeadlineExceeded, "RPC timeout")
-			} else {
-				log.Error().Err(err).Msg("rpc.GetPrivateChat.canceled")
-				return nil, status.Error(codes.Canceled, "RPC canceled by server")
+		for i := range res {
+			if err := stream.Send(&telegrampb.StreamPrivateChatsResponse{Data: chatDataFromPrivateChat(&res[i])}); err != nil {
+				log.Ctx(ctx).Error().Err(err).Int("sent", sent).Msg("rpc.StreamPrivateChats.send")
+				return err
 			}
+			sent++
+		}
 
-		// something happened when fetching from database
-		case err := <-errChan:
-			log.Error().Err(err).Msg("rpc.GetPrivateChat.database")
-			return nil, status.Error(codes.Internal, "An error occured when querying to database")
+		// last batch
+		if len(res) < STREAM_BATCH_SIZE {
+			log.Ctx(ctx).Info().Int("sent", sent).Msg("rpc.StreamPrivateChats.result")
+			return nil
+		}
 
-		// fatal error happened
-		case err := <-fatalErr:
-			log.Error().Err(err).Msg("rpc.GetPrivateChat.FATAL")
-			return nil, status.Error(codes.Internal, "Fatal internal server error")
+		afterChatId = res[len(res)-1].ChatID
+	}
+}
 
-		// successful case
-		case res := <-result:
-			log.Info().Interface("db_result", res).Msg("rpc.GetPrivateChat.result")
+// build query filter from request filter fields, empty fields are ignored
+func privateChatFilter(chatId, username string) datasource.QueryFilter {
+	filter := datasource.NewQueryFilter()
 
-			// check if there's any result to avoid nil pointer deref
-			if len(res) == 0 {
-				return nil, status.Error(codes.NotFound, "No chats found.")
-			}
+	if len(chatId) != 0 {
+		filter["chat_id"] = chatId
+	}
 
-			// iterate to assign values
-			pbOut := &telegrampb.GetPrivateChatResponse{}
-			for i := range res {
-				chatData := &telegrampb.ChatData{
-					ChatId:      res[i].ChatID,
-					Username:    res[i].Username,
-					DisplayName: res[i].Name,
-					Bio:         res[i].Bio,
-				}
-
-				pbOut.Data = append(pbOut.Data, chatData)
-			}
-			pbOut.Count = uint64(len(res))
+	if len(username) != 0 {
+		filter["username"] = username
+	}
 
-			return pbOut, nil
-		}
+	return filter
+}
+
+func chatDataFromPrivateChat(chat *datasource.PrivateChat) *telegrampb.ChatData {
+	return &telegrampb.ChatData{
+		ChatId:       chat.ChatID,
+		Username:     chat.Username,
+		DisplayName:  chat.Name,
+		Bio:          chat.Bio,
+		LanguageCode: chat.LanguageCode,
+		IsBlocked:    chat.IsBlocked,
+		CreatedAt:    timestamppb.New(chat.CreatedAt),
+		UpdatedAt:    timestamppb.New(chat.UpdatedAt),
+		LastSeenAt:   optionalTimestamp(chat.LastSeenAt),
+		StartedAt:    optionalTimestamp(chat.StartedAt),
+		StoppedAt:    optionalTimestamp(chat.StoppedAt),
 	}
 }
+
+// nullable column maps to unset timestamp
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
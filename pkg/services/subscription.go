@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	telegrampb "github.com/yeyee2901/proto-lord-bidoof-bot/gen/go/telegram/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var subscriptionEventToPb = map[string]telegrampb.SubscriptionEventType{
+	datasource.SUBSCRIPTION_STARTED:   telegrampb.SubscriptionEventType_SUBSCRIPTION_EVENT_TYPE_STARTED,
+	datasource.SUBSCRIPTION_STOPPED:   telegrampb.SubscriptionEventType_SUBSCRIPTION_EVENT_TYPE_STOPPED,
+	datasource.SUBSCRIPTION_BLOCKED:   telegrampb.SubscriptionEventType_SUBSCRIPTION_EVENT_TYPE_BLOCKED,
+	datasource.SUBSCRIPTION_UNBLOCKED: telegrampb.SubscriptionEventType_SUBSCRIPTION_EVENT_TYPE_UNBLOCKED,
+}
+
+// List subscription history (start, stop, block, unblock), paginated by
+// `page_size` & `page_token`
+func (se *Services) ListSubscriptionEvents(ctx context.Context, pbIn *telegrampb.ListSubscriptionEventsRequest) (*telegrampb.ListSubscriptionEventsResponse, error) {
+	filter := datasource.SubscriptionEventFilter{
+		ChatID: pbIn.GetFilterChatId(),
+	}
+
+	if pbIn.StartTime != nil {
+		filter.From = pbIn.GetStartTime().AsTime()
+	}
+
+	if pbIn.EndTime != nil {
+		filter.To = pbIn.GetEndTime().AsTime()
+	}
+
+	if e := pbIn.GetFilterEvent(); e != telegrampb.SubscriptionEventType_SUBSCRIPTION_EVENT_TYPE_UNSPECIFIED {
+		for k, v := range subscriptionEventToPb {
+			if v == e {
+				filter.Event = k
+			}
+		}
+
+		if len(filter.Event) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "Unknown event type")
+		}
+	}
+
+	// check pagination
+	limit := pageSize(pbIn.GetPageSize())
+	afterId, err := decodePageToken(pbIn.GetPageToken())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("page_token", pbIn.GetPageToken()).Msg("rpc.ListSubscriptionEvents.pageToken")
+		return nil, status.Error(codes.InvalidArgument, "Invalid page token")
+	}
+
+	// fetch one extra row to know whether there is a next page
+	res, err := se.DataSource.GetSubscriptionEvents(ctx, filter, afterId, limit+1)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.ListSubscriptionEvents.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	pbOut := &telegrampb.ListSubscriptionEventsResponse{
+		Events: []*telegrampb.SubscriptionEvent{},
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+		pbOut.NextPageToken = encodePageToken(res[limit-1].ID)
+	}
+
+	for i := range res {
+		pbOut.Events = append(pbOut.Events, &telegrampb.SubscriptionEvent{
+			Id:        res[i].ID,
+			ChatId:    res[i].ChatID,
+			Event:     subscriptionEventToPb[res[i].Event],
+			CreatedAt: timestamppb.New(res[i].CreatedAt),
+		})
+	}
+
+	return pbOut, nil
+}
+
+// Summarize subscription churn within a time window
+func (se *Services) GetSubscriptionChurn(ctx context.Context, pbIn *telegrampb.GetSubscriptionChurnRequest) (*telegrampb.GetSubscriptionChurnResponse, error) {
+	var filter datasource.SubscriptionEventFilter
+
+	if pbIn.StartTime != nil {
+		filter.From = pbIn.GetStartTime().AsTime()
+	}
+
+	if pbIn.EndTime != nil {
+		filter.To = pbIn.GetEndTime().AsTime()
+	}
+
+	counts, err := se.DataSource.CountSubscriptionEvents(ctx, filter)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.GetSubscriptionChurn.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	active, err := se.DataSource.CountActivePrivateChat(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("rpc.GetSubscriptionChurn.database")
+		return nil, status.Error(codes.Internal, "An error occured when querying to database")
+	}
+
+	pbOut := &telegrampb.GetSubscriptionChurnResponse{
+		Started:   counts[datasource.SUBSCRIPTION_STARTED],
+		Stopped:   counts[datasource.SUBSCRIPTION_STOPPED],
+		Blocked:   counts[datasource.SUBSCRIPTION_BLOCKED],
+		Unblocked: counts[datasource.SUBSCRIPTION_UNBLOCKED],
+		Active:    active,
+	}
+	pbOut.Net = int64(pbOut.Started+pbOut.Unblocked) - int64(pbOut.Stopped+pbOut.Blocked)
+
+	return pbOut, nil
+}
@@ -11,6 +11,27 @@ type RespGetMe struct {
 }
 
 type RespSendMessage struct {
+	// first message, when the text is split
+	MessageID  int64
+	MessageIDs []int64
+	Recipient  string
+}
+
+// nil when nothing was sent yet
+func (r *RespSendMessage) partial() *RespSendMessage {
+	if len(r.MessageIDs) == 0 {
+		return nil
+	}
+
+	return r
+}
+
+type RespSendMedia struct {
 	MessageID int64
-	Recipient string
+	FileID    string
+}
+
+type RespSendMediaGroup struct {
+	MessageIDs []int64
+	FileIDs    []string
 }
@@ -1,16 +1,125 @@
 package telegram
 
-import "google.golang.org/grpc/codes"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// domain of ErrorInfo details attached to Telegram errors
+const ERROR_DOMAIN = "api.telegram.org"
+
+// machine readable reason of a Telegram error, sent as ErrorInfo.reason
+const (
+	REASON_BOT_BLOCKED          = "BOT_BLOCKED"
+	REASON_CHAT_NOT_FOUND       = "CHAT_NOT_FOUND"
+	REASON_MESSAGE_NOT_FOUND    = "MESSAGE_NOT_FOUND"
+	REASON_MESSAGE_NOT_MODIFIED = "MESSAGE_NOT_MODIFIED"
+	REASON_MESSAGE_IMMUTABLE    = "MESSAGE_IMMUTABLE"
+	REASON_NOT_ENOUGH_RIGHTS    = "NOT_ENOUGH_RIGHTS"
+	REASON_NOT_MEMBER           = "BOT_NOT_MEMBER"
+	REASON_INVALID_FILE         = "INVALID_FILE"
+	REASON_BAD_REQUEST          = "BAD_REQUEST"
+	REASON_FORBIDDEN            = "FORBIDDEN"
+	REASON_NOT_FOUND            = "NOT_FOUND"
+	REASON_UNAUTHORIZED         = "UNAUTHORIZED"
+	REASON_CONFLICT             = "CONFLICT"
+	REASON_RATE_LIMITED         = "RATE_LIMITED"
+	REASON_UNAVAILABLE          = "TELEGRAM_UNAVAILABLE"
+	REASON_TIMEOUT              = "TIMEOUT"
+	REASON_CANCELED             = "CANCELED"
+	REASON_UNKNOWN              = "UNKNOWN"
+)
+
+// Failure of a Bot API call, converted to gRPC status with ErrorInfo (and
+// RetryInfo when rate limited) when returned from an RPC
 type TelegramError struct {
 	GrpcCode codes.Code
-	details  string
+
+	// error code returned by Telegram, 0 if the request never got a response
+	Code   int
+	Reason string
+
+	// how long to wait before retrying, only when rate limited
+	RetryAfter time.Duration
+
+	// messages delivered before the failure, when a split message failed
+	// partway
+	SentMessageIDs []int64
+
+	details string
 }
 
 func (e *TelegramError) Error() string {
 	return e.details
 }
 
+func (e *TelegramError) GRPCStatus() *status.Status {
+	st := status.New(e.GrpcCode, e.details)
+
+	info := &errdetails.ErrorInfo{
+		Reason:   e.Reason,
+		Domain:   ERROR_DOMAIN,
+		Metadata: map[string]string{"telegram_code": fmt.Sprint(e.Code)},
+	}
+	if len(e.SentMessageIDs) != 0 {
+		ids := make([]string, len(e.SentMessageIDs))
+		for i, id := range e.SentMessageIDs {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		info.Metadata["sent_message_ids"] = strings.Join(ids, ",")
+	}
+
+	var (
+		withDetails *status.Status
+		err         error
+	)
+	if e.RetryAfter > 0 {
+		withDetails, err = st.WithDetails(info, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	} else {
+		withDetails, err = st.WithDetails(info)
+	}
+
+	// details can't really fail to marshal, keep the bare status anyway
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// Attach messages delivered before `err`, see TelegramError.SentMessageIDs.
+// Timeouts & cancellations carry no TelegramError, one is made for them.
+func WithSentMessages(err error, sent []int64) error {
+	var tgErr *TelegramError
+	if !errors.As(err, &tgErr) {
+		st := status.Convert(err)
+		tgErr = &TelegramError{GrpcCode: st.Code(), Reason: REASON_UNKNOWN, details: st.Message()}
+
+		switch st.Code() {
+		case codes.DeadlineExceeded:
+			tgErr.Reason = REASON_TIMEOUT
+		case codes.Canceled:
+			tgErr.Reason = REASON_CANCELED
+		}
+		err = tgErr
+	}
+
+	tgErr.SentMessageIDs = sent
+
+	return err
+}
+
 type ServerError struct {
 	details string
 }
@@ -18,3 +127,107 @@ type ServerError struct {
 func (e *ServerError) Error() string {
 	return e.details
 }
+
+// Telegram tells what exactly went wrong only in the description, matched by
+// substring in order
+var badRequestReasons = []struct {
+	description string
+	reason      string
+	code        codes.Code
+}{
+	{"chat not found", REASON_CHAT_NOT_FOUND, codes.NotFound},
+	{"message to edit not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to delete not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to forward not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to copy not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to pin not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to unpin not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message to be replied not found", REASON_MESSAGE_NOT_FOUND, codes.NotFound},
+	{"message is not modified", REASON_MESSAGE_NOT_MODIFIED, codes.FailedPrecondition},
+	{"can't be edited", REASON_MESSAGE_IMMUTABLE, codes.FailedPrecondition},
+	{"can't be deleted", REASON_MESSAGE_IMMUTABLE, codes.FailedPrecondition},
+	{"can't be forwarded", REASON_MESSAGE_IMMUTABLE, codes.FailedPrecondition},
+	{"not enough rights", REASON_NOT_ENOUGH_RIGHTS, codes.PermissionDenied},
+	{"bot is not a member", REASON_NOT_MEMBER, codes.PermissionDenied},
+	{"wrong file", REASON_INVALID_FILE, codes.InvalidArgument},
+	{"failed to get http url content", REASON_INVALID_FILE, codes.InvalidArgument},
+	{"wrong remote file", REASON_INVALID_FILE, codes.InvalidArgument},
+}
+
+var forbiddenReasons = []struct {
+	description string
+	reason      string
+}{
+	{"bot was blocked", REASON_BOT_BLOCKED},
+	{"user is deactivated", REASON_BOT_BLOCKED},
+	{"not enough rights", REASON_NOT_ENOUGH_RIGHTS},
+	{"bot is not a member", REASON_NOT_MEMBER},
+}
+
+// Classify error returned by tgbotapi. Errors without Telegram response
+// (network, timeout from the HTTP client, ...) are treated as Telegram being
+// unavailable.
+func ParseError(err error) *TelegramError {
+	var tgErr *TelegramError
+	if errors.As(err, &tgErr) {
+		return tgErr
+	}
+
+	// transport errors quote the request URL, which holds the token
+	e := &TelegramError{
+		GrpcCode: codes.Unavailable,
+		Reason:   REASON_UNAVAILABLE,
+		details:  debug.RedactBotToken(err.Error()),
+	}
+
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return e
+	}
+
+	e.Code = apiErr.Code
+	description := strings.ToLower(apiErr.Message)
+
+	switch {
+	case apiErr.Code == http.StatusBadRequest:
+		e.GrpcCode, e.Reason = codes.InvalidArgument, REASON_BAD_REQUEST
+		for _, r := range badRequestReasons {
+			if strings.Contains(description, r.description) {
+				e.GrpcCode, e.Reason = r.code, r.reason
+				break
+			}
+		}
+
+	case apiErr.Code == http.StatusForbidden:
+		e.GrpcCode, e.Reason = codes.PermissionDenied, REASON_FORBIDDEN
+		for _, r := range forbiddenReasons {
+			if strings.Contains(description, r.description) {
+				e.Reason = r.reason
+				break
+			}
+		}
+
+	// token is wrong or revoked, not something the caller can fix
+	case apiErr.Code == http.StatusUnauthorized:
+		e.GrpcCode, e.Reason = codes.Internal, REASON_UNAUTHORIZED
+
+	case apiErr.Code == http.StatusNotFound:
+		e.GrpcCode, e.Reason = codes.NotFound, REASON_NOT_FOUND
+
+	// another instance is polling updates with the same token
+	case apiErr.Code == http.StatusConflict:
+		e.GrpcCode, e.Reason = codes.Aborted, REASON_CONFLICT
+
+	case apiErr.Code == http.StatusTooManyRequests:
+		e.GrpcCode, e.Reason = codes.ResourceExhausted, REASON_RATE_LIMITED
+		e.RetryAfter = time.Duration(apiErr.RetryAfter) * time.Second
+
+	case apiErr.Code >= http.StatusInternalServerError:
+		e.GrpcCode, e.Reason = codes.Unavailable, REASON_UNAVAILABLE
+
+	default:
+		e.GrpcCode, e.Reason = codes.Unknown, REASON_UNKNOWN
+	}
+
+	return e
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		In     error
+		Code   codes.Code
+		Reason string
+	}{
+		{"blocked", &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}, codes.PermissionDenied, REASON_BOT_BLOCKED},
+		{"chat_not_found", &tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"}, codes.NotFound, REASON_CHAT_NOT_FOUND},
+		{"message_not_found", &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"}, codes.NotFound, REASON_MESSAGE_NOT_FOUND},
+		{"user_not_found", &tgbotapi.Error{Code: 400, Message: "Bad Request: user not found"}, codes.InvalidArgument, REASON_BAD_REQUEST},
+		{"not_member", &tgbotapi.Error{Code: 400, Message: "Bad Request: bot is not a member of the channel chat"}, codes.PermissionDenied, REASON_NOT_MEMBER},
+		{"http_not_found", &tgbotapi.Error{Code: 404, Message: "Not Found"}, codes.NotFound, REASON_NOT_FOUND},
+		{"too_old", &tgbotapi.Error{Code: 400, Message: "Bad Request: message can't be deleted for everyone"}, codes.FailedPrecondition, REASON_MESSAGE_IMMUTABLE},
+		{"bad_request", &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities"}, codes.InvalidArgument, REASON_BAD_REQUEST},
+		{"unauthorized", &tgbotapi.Error{Code: 401, Message: "Unauthorized"}, codes.Internal, REASON_UNAUTHORIZED},
+		{"server_error", &tgbotapi.Error{Code: 502, Message: "Bad Gateway"}, codes.Unavailable, REASON_UNAVAILABLE},
+		{"network", errors.New("dial tcp: i/o timeout"), codes.Unavailable, REASON_UNAVAILABLE},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ParseError(tc.In)
+			assert.Equal(t, tc.Code, status.Code(err))
+			assert.Equal(t, tc.Reason, err.Reason)
+		})
+	}
+}
+
+func TestParseErrorRateLimited(t *testing.T) {
+	err := ParseError(&tgbotapi.Error{
+		Code:               429,
+		Message:            "Too Many Requests: retry after 5",
+		ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5},
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+
+	var retryDelay time.Duration
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.RetryInfo:
+			retryDelay = d.GetRetryDelay().AsDuration()
+		case *errdetails.ErrorInfo:
+			assert.Equal(t, REASON_RATE_LIMITED, d.GetReason())
+			assert.Equal(t, "429", d.GetMetadata()["telegram_code"])
+		}
+	}
+	assert.Equal(t, 5*time.Second, retryDelay)
+}
+
+func TestParseErrorRedactsToken(t *testing.T) {
+	err := ParseError(&url.Error{
+		Op:  "Post",
+		URL: "https://api.telegram.org/bot123456:AAE-secret_token/sendMessage",
+		Err: errors.New("dial tcp: i/o timeout"),
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NotContains(t, status.Convert(err).Message(), "secret")
+	assert.Contains(t, err.Error(), "bot[REDACTED]/sendMessage")
+}
+
+func TestWithSentMessages(t *testing.T) {
+	err := WithSentMessages(status.Error(codes.DeadlineExceeded, "Timeout"), []int64{11, 12})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.DeadlineExceeded, st.Code())
+	assert.Equal(t, "Timeout", st.Message())
+	if assert.Len(t, st.Details(), 1) {
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, REASON_TIMEOUT, info.GetReason())
+		assert.Equal(t, "11,12", info.GetMetadata()["sent_message_ids"])
+	}
+}
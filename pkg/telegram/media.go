@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/async"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
+)
+
+// caption of a media, formatted either by `ParseMode` or by `Entities`
+type Caption struct {
+	Text      string
+	ParseMode render.ParseMode
+	Entities  []tgbotapi.MessageEntity
+}
+
+// send photo from url, file ID or uploaded bytes
+func (t *TelegramService) SendPhoto(ctx context.Context, chatId int64, file tgbotapi.RequestFileData, caption Caption) (*RespSendMedia, error) {
+	photo := tgbotapi.NewPhoto(chatId, file)
+	photo.Caption = caption.Text
+	photo.ParseMode = string(caption.ParseMode)
+	photo.CaptionEntities = caption.Entities
+
+	return t.sendMedia(ctx, "sendPhoto", photo)
+}
+
+// send general file from url, file ID or uploaded bytes
+func (t *TelegramService) SendDocument(ctx context.Context, chatId int64, file tgbotapi.RequestFileData, caption Caption) (*RespSendMedia, error) {
+	document := tgbotapi.NewDocument(chatId, file)
+	document.Caption = caption.Text
+	document.ParseMode = string(caption.ParseMode)
+	document.CaptionEntities = caption.Entities
+
+	return t.sendMedia(ctx, "sendDocument", document)
+}
+
+// send sticker from url, file ID or uploaded bytes
+func (t *TelegramService) SendSticker(ctx context.Context, chatId int64, file tgbotapi.RequestFileData) (*RespSendMedia, error) {
+	return t.sendMedia(ctx, "sendSticker", tgbotapi.NewSticker(chatId, file))
+}
+
+// send photos or documents as an album, `media` items are either
+// tgbotapi.InputMediaPhoto or tgbotapi.InputMediaDocument
+func (t *TelegramService) SendMediaGroup(ctx context.Context, chatId int64, media []any) (*RespSendMediaGroup, error) {
+	res, err := async.Run(ctx, t.timeout(), func(ctx context.Context) (*RespSendMediaGroup, error) {
+		messages, err := tracing.TelegramCall(ctx, "sendMediaGroup", func() ([]tgbotapi.Message, error) {
+			return t.BotAPI.SendMediaGroup(tgbotapi.NewMediaGroup(chatId, media))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res := new(RespSendMediaGroup)
+		for i := range messages {
+			t.recordMessage(ctx, &messages[i])
+			res.MessageIDs = append(res.MessageIDs, int64(messages[i].MessageID))
+			res.FileIDs = append(res.FileIDs, fileID(&messages[i]))
+		}
+
+		return res, nil
+	})
+	if err != nil {
+		return nil, callError(ctx, err)
+	}
+
+	return res, nil
+}
+
+// `method` is the Bot API method of `media`, used as span name
+func (t *TelegramService) sendMedia(ctx context.Context, method string, media tgbotapi.Chattable) (*RespSendMedia, error) {
+	res, err := async.Run(ctx, t.timeout(), func(ctx context.Context) (*RespSendMedia, error) {
+		m, err := tracing.TelegramCall(ctx, method, func() (tgbotapi.Message, error) {
+			return t.BotAPI.Send(media)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		t.recordMessage(ctx, &m)
+
+		return &RespSendMedia{int64(m.MessageID), fileID(&m)}, nil
+	})
+	if err != nil {
+		return nil, callError(ctx, err)
+	}
+
+	return res, nil
+}
+
+// ID of the sent file, photos come in several sizes and the largest is last
+func fileID(m *tgbotapi.Message) string {
+	switch {
+	case len(m.Photo) != 0:
+		return m.Photo[len(m.Photo)-1].FileID
+	case m.Document != nil:
+		return m.Document.FileID
+	case m.Sticker != nil:
+		return m.Sticker.FileID
+	default:
+		return ""
+	}
+}
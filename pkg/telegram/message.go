@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/async"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// replace text of a message sent by the bot, formatted either by `parseMode`
+// or by `entities` (with PARSE_MODE_PLAIN)
+func (t *TelegramService) EditMessageText(ctx context.Context, chatId int64, messageId int, text string, parseMode render.ParseMode, entities []tgbotapi.MessageEntity) error {
+	edit := tgbotapi.NewEditMessageText(chatId, messageId, text)
+	edit.ParseMode = string(parseMode)
+	edit.Entities = entities
+
+	_, err := t.request(ctx, "editMessageText", edit)
+
+	return err
+}
+
+func (t *TelegramService) DeleteMessage(ctx context.Context, chatId int64, messageId int) error {
+	_, err := t.request(ctx, "deleteMessage", tgbotapi.NewDeleteMessage(chatId, messageId))
+
+	return err
+}
+
+func (t *TelegramService) PinMessage(ctx context.Context, chatId int64, messageId int, disableNotification bool) error {
+	_, err := t.request(ctx, "pinChatMessage", tgbotapi.PinChatMessageConfig{
+		ChatID:              chatId,
+		MessageID:           messageId,
+		DisableNotification: disableNotification,
+	})
+
+	return err
+}
+
+// unpin `messageId`, or the most recently pinned message if it's 0
+func (t *TelegramService) UnpinMessage(ctx context.Context, chatId int64, messageId int) error {
+	_, err := t.request(ctx, "unpinChatMessage", tgbotapi.UnpinChatMessageConfig{
+		ChatID:    chatId,
+		MessageID: messageId,
+	})
+
+	return err
+}
+
+// forward message `messageId` of `fromChatId` to `chatId`, returns ID of the
+// forwarded copy
+func (t *TelegramService) ForwardMessage(ctx context.Context, chatId, fromChatId int64, messageId int, disableNotification bool) (int64, error) {
+	forward := tgbotapi.NewForward(chatId, fromChatId, messageId)
+	forward.DisableNotification = disableNotification
+
+	result, err := t.request(ctx, "forwardMessage", forward)
+	if err != nil {
+		return 0, err
+	}
+
+	var m tgbotapi.Message
+	if err := json.Unmarshal(result, &m); err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	t.recordMessage(ctx, &m)
+
+	return int64(m.MessageID), nil
+}
+
+// make Bot API request within the bot timeout, returns the raw result.
+// `method` is the Bot API method of `c`, used as span name. Telegram errors
+// are returned as *TelegramError.
+func (t *TelegramService) request(ctx context.Context, method string, c tgbotapi.Chattable) (json.RawMessage, error) {
+	res, err := async.Run(ctx, t.timeout(), func(ctx context.Context) (json.RawMessage, error) {
+		resp, err := tracing.TelegramCall(ctx, method, func() (*tgbotapi.APIResponse, error) {
+			return t.BotAPI.Request(c)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return resp.Result, nil
+	})
+	if err != nil {
+		return nil, callError(ctx, err)
+	}
+
+	return res, nil
+}
@@ -2,11 +2,16 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/async"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/tracing"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -22,94 +27,107 @@ func NewTelegramService(ds *datasource.DataSource, bot *tgbotapi.BotAPI) *Telegr
 
 // get the bot status
 func (t *TelegramService) GetBotStatus(ctx context.Context) (*RespGetMe, error) {
-	// goroutine goes brrrr
-	thisCtx, cancel := context.WithTimeout(ctx, time.Duration(t.Config.Telegram.Bot.Timeout)*time.Second)
-	defer cancel()
-	errChan := make(chan error)
-	result := make(chan *RespGetMe)
-
-	// task goroutine
-	go func() {
-		if user, err := t.BotAPI.GetMe(); err != nil {
-			errChan <- err
-		} else {
-			result <- &RespGetMe{
-				Id:                      uint64(user.ID),
-				IsBot:                   user.IsBot,
-				FirstName:               user.FirstName,
-				LastName:                user.LastName,
-				Username:                user.UserName,
-				CanJoinGroups:           user.CanJoinGroups,
-				CanReadAllGroupMessages: user.CanReadAllGroupMessages,
-			}
+	res, err := async.Run(ctx, t.timeout(), func(ctx context.Context) (*RespGetMe, error) {
+		user, err := tracing.TelegramCall(ctx, "getMe", t.BotAPI.GetMe)
+		if err != nil {
+			return nil, err
 		}
-	}()
 
-	for {
-		select {
-
-		// task timeout, or somehow canceled
-		case <-thisCtx.Done():
-			if err := thisCtx.Err(); err == context.DeadlineExceeded {
-				return nil, status.Error(codes.DeadlineExceeded, "Timeout")
-			} else {
-				return nil, status.Error(codes.Canceled, err.Error())
-			}
+		return &RespGetMe{
+			Id:                      uint64(user.ID),
+			IsBot:                   user.IsBot,
+			FirstName:               user.FirstName,
+			LastName:                user.LastName,
+			Username:                user.UserName,
+			CanJoinGroups:           user.CanJoinGroups,
+			CanReadAllGroupMessages: user.CanReadAllGroupMessages,
+		}, nil
+	})
+	if err != nil {
+		return nil, callError(ctx, err)
+	}
 
-		// error from telegram
-		case err := <-errChan:
-			return nil, status.Error(codes.Aborted, err.Error())
+	return res, nil
+}
 
-		case res := <-result:
-			return res, nil
+// send chat to user with `chatId`, formatted either by `parseMode` or by
+// `entities` (with PARSE_MODE_PLAIN). Message over Telegram's limit is split
+// and sent in order. If a chunk fails after earlier ones were delivered, the
+// delivered messages are returned along with the error.
+func (t *TelegramService) SendChat(ctx context.Context, chatId int64, message string, parseMode render.ParseMode, entities []tgbotapi.MessageEntity) (*RespSendMessage, error) {
+	var (
+		chunks        []string
+		chunkEntities [][]tgbotapi.MessageEntity
+	)
+	if len(entities) != 0 {
+		chunks, chunkEntities = render.SplitWithEntities(message, entities, render.MAX_MESSAGE_LENGTH)
+	} else {
+		var err error
+		if chunks, err = render.Split(parseMode, message, render.MAX_MESSAGE_LENGTH); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+		chunkEntities = make([][]tgbotapi.MessageEntity, len(chunks))
 	}
-}
-
-// send chat to user with `chatId`
-func (t *TelegramService) SendChat(ctx context.Context, chatId int64, message string, useMarkdown bool) (*RespSendMessage, error) {
-	// goroutine goes brrrr
-	chatCtx, cancel := context.WithTimeout(ctx, time.Duration(t.Config.Telegram.Bot.Timeout)*time.Second)
-	defer cancel()
-	errChan := make(chan error)
-	result := make(chan *RespSendMessage)
 
-	// send chat task
-	go func() {
-		toSend := tgbotapi.NewMessage(chatId, message)
-
-		if useMarkdown {
-			toSend.ParseMode = tgbotapi.ModeMarkdownV2
+	// every chunk gets its own bot timeout. Delivered chunks are kept out of
+	// async.Run, so they're still returned when a later one times out.
+	res := new(RespSendMessage)
+	for i := range chunks {
+		// don't keep sending after the caller gave up
+		if err := ctx.Err(); err != nil {
+			return res.partial(), callError(ctx, err)
 		}
 
-		if m, err := t.BotAPI.Send(toSend); err != nil {
-			errChan <- err
-		} else {
-			result <- &RespSendMessage{
-				MessageID: int64(m.MessageID),
-				Recipient: fmt.Sprintf("%s %s", m.Chat.FirstName, m.Chat.LastName),
-			}
+		toSend := tgbotapi.NewMessage(chatId, chunks[i])
+		toSend.ParseMode = string(parseMode)
+		toSend.Entities = chunkEntities[i]
+
+		m, err := async.Run(ctx, t.timeout(), func(ctx context.Context) (tgbotapi.Message, error) {
+			return tracing.TelegramCall(ctx, "sendMessage", func() (tgbotapi.Message, error) {
+				return t.BotAPI.Send(toSend)
+			})
+		})
+		if err != nil {
+			return res.partial(), callError(ctx, err)
 		}
-	}()
-
-	// poll goroutine
-	for {
-		select {
-		// timeout, or context get canceled somehow
-		case <-chatCtx.Done():
-			if err := chatCtx.Err(); err == context.DeadlineExceeded {
-				return nil, status.Error(codes.DeadlineExceeded, "Timeout")
-			} else {
-				return nil, status.Error(codes.Canceled, err.Error())
-			}
-
-		// case: error, something happened, the source must be from Telegram API
-		case err := <-errChan:
-			return nil, status.Error(codes.Aborted, err.Error())
-
-		// case: sending chat success
-		case resp := <-result:
-			return resp, nil
+
+		t.recordMessage(ctx, &m)
+
+		if i == 0 {
+			res.MessageID = int64(m.MessageID)
+			res.Recipient = fmt.Sprintf("%s %s", m.Chat.FirstName, m.Chat.LastName)
 		}
+		res.MessageIDs = append(res.MessageIDs, int64(m.MessageID))
+	}
+
+	return res, nil
+}
+
+// every Bot API call is bounded by the bot timeout
+func (t *TelegramService) timeout() time.Duration {
+	return t.Config.Get().Telegram.Bot.Timeout.Duration()
+}
+
+// convert error of a Bot API call made with async.Run to gRPC status
+func callError(ctx context.Context, err error) error {
+	var panicErr *async.PanicError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "Timeout")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.As(err, &panicErr):
+		log.Ctx(ctx).Error().Err(err).Bytes("stack", panicErr.Stack).Msg("telegram.panic")
+		return status.Error(codes.Internal, "Fatal internal server error")
+	default:
+		return ParseError(err)
+	}
+}
+
+// failing to record history must not fail the send
+func (t *TelegramService) recordMessage(ctx context.Context, m *tgbotapi.Message) {
+	if err := t.InsertMessage(ctx, datasource.NewMessage(m, datasource.MESSAGE_OUTBOUND)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int64("chat_id", m.Chat.ID).Msg("history.insert")
 	}
 }
@@ -2,20 +2,32 @@ package telegram
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	_ "github.com/go-sql-driver/mysql"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/datasource"
 	"github.com/yeyee2901/lord-bidoof-bot/pkg/debug"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/render"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGetBotStatus(t *testing.T) {
-	cfg := config.LoadConfig()
-	ds := datasource.NewDataSource(&cfg, nil, nil)
-	bot, err := tgbotapi.NewBotAPI(os.Getenv(cfg.Telegram.TokenEnv))
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := datasource.NewDataSource(config.NewStore(cfg, config.Options{}), nil, nil)
+	bot, err := tgbotapi.NewBotAPI(cfg.Telegram.Token.Value.Reveal())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -28,3 +40,90 @@ func TestGetBotStatus(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// service talking to a fake Bot API served by `handler`, with an unreachable
+// database so history writes fail fast
+func newFakeService(t *testing.T, handler func(method string, w http.ResponseWriter)) *TelegramService {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+		if method == "getMe" {
+			fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"bidoof"}}`)
+			return
+		}
+		handler(method, w)
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := tgbotapi.NewBotAPIWithClient("123:SECRET", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.AppConfig
+	cfg.Telegram.Bot.Timeout = config.Duration(5 * time.Second)
+	db := sqlx.MustOpen("mysql", "bidoof@tcp(127.0.0.1:1)/bidoof")
+	t.Cleanup(func() { db.Close() })
+
+	return NewTelegramService(datasource.NewDataSource(config.NewStore(cfg, config.Options{}), db, nil), bot)
+}
+
+func TestSendChatPartial(t *testing.T) {
+	var sent int
+	tg := newFakeService(t, func(method string, w http.ResponseWriter) {
+		sent++
+		if sent > 1 {
+			fmt.Fprint(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"date":0,"chat":{"id":1234,"type":"private"}}}`, 10+sent)
+	})
+
+	// two chunks
+	text := strings.Repeat("bidoof ", render.MAX_MESSAGE_LENGTH/4)
+	res, err := tg.SendChat(context.Background(), 1234, text, render.PARSE_MODE_PLAIN, nil)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	if assert.NotNil(t, res) {
+		assert.Equal(t, []int64{11}, res.MessageIDs)
+	}
+}
+
+func TestSendChatPartialTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	var sent int
+	tg := newFakeService(t, func(method string, w http.ResponseWriter) {
+		sent++
+		if sent > 1 {
+			<-release // second chunk stalls
+			return
+		}
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"date":0,"chat":{"id":1234,"type":"private"}}}`, 10+sent)
+	})
+	tg.Config.Get().Telegram.Bot.Timeout = config.Duration(100 * time.Millisecond)
+	t.Cleanup(func() { close(release) }) // before the server closes
+
+	text := strings.Repeat("bidoof ", render.MAX_MESSAGE_LENGTH/4)
+	res, err := tg.SendChat(context.Background(), 1234, text, render.PARSE_MODE_PLAIN, nil)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	if assert.NotNil(t, res) {
+		assert.Equal(t, []int64{11}, res.MessageIDs)
+	}
+}
+
+func TestSendChatUnsplittable(t *testing.T) {
+	var sent int
+	tg := newFakeService(t, func(method string, w http.ResponseWriter) {
+		sent++
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":1234,"type":"private"}}}`)
+	})
+
+	// link text alone is over the limit
+	text := "aaaa [" + strings.Repeat("b", render.MAX_MESSAGE_LENGTH) + "](https://x.y)"
+	res, err := tg.SendChat(context.Background(), 1234, text, render.PARSE_MODE_MARKDOWN_V2, nil)
+
+	assert.Nil(t, res)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Zero(t, sent)
+}
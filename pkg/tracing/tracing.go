@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// span exporters, set by `tracing.exporter` in setting.yaml
+const (
+	EXPORTER_NONE   = "none"
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_OTLP   = "otlp"
+)
+
+const INSTRUMENTATION_NAME = "github.com/yeyee2901/lord-bidoof-bot"
+
+// global tracer delegates to whatever provider Init installs, so spans can be
+// started before (or without) Init
+var tracer = otel.Tracer(INSTRUMENTATION_NAME)
+
+// Install global tracer provider exporting spans of `service`. The returned
+// function flushes pending spans & must be called before exiting. Exporter
+// "none" (or empty) leaves tracing disabled.
+func Init(cfg config.AppConfig, service string) (shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+
+	switch cfg.Tracing.Exporter {
+	case EXPORTER_NONE, "":
+		return func(context.Context) error { return nil }, nil
+
+	case EXPORTER_STDOUT:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+
+	case EXPORTER_OTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Tracing.Endpoint)}
+		if cfg.Tracing.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		// connects lazily, an unreachable collector doesn't block startup
+		exporter, err = otlptracegrpc.New(context.Background(), opts...)
+
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Tracing.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(service))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start child span of ctx. The returned context also carries a logger with
+// trace_id & span_id, log through `log.Ctx(ctx)` to correlate entries with
+// the trace.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+
+	return withLogger(ctx, span.SpanContext()), span
+}
+
+// End span, marking it failed if `err` is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Run Bot API call as child span of ctx. tgbotapi doesn't take a context, so
+// the span can't be started by the HTTP client.
+func TelegramCall[T any](ctx context.Context, method string, call func() (T, error)) (T, error) {
+	_, span := tracer.Start(ctx, "telegram."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("telegram.method", method)),
+	)
+
+	res, err := call()
+	End(span, err)
+
+	return res, err
+}
+
+// sampled out spans have no valid ID, nothing to correlate with
+func withLogger(ctx context.Context, sc trace.SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Str("trace_id", sc.TraceID().String()).
+		Str("span_id", sc.SpanID().String()).
+		Logger()
+
+	return logger.WithContext(ctx)
+}
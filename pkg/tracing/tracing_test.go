@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeyee2901/lord-bidoof-bot/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// the global provider can only be delegated to once
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	os.Exit(m.Run())
+}
+
+func ended(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %q not ended", name)
+	return nil
+}
+
+func TestInit(t *testing.T) {
+	var cfg config.AppConfig
+
+	shutdown, err := Init(cfg, "test")
+	if assert.NoError(t, err) {
+		assert.NoError(t, shutdown(context.Background()))
+	}
+
+	cfg.Tracing.Exporter = "jaeger"
+	_, err = Init(cfg, "test")
+	assert.Error(t, err)
+}
+
+func TestStartLogsTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).WithContext(context.Background())
+
+	ctx, span := Start(ctx, "test.log")
+	zerolog.Ctx(ctx).Info().Msg("hello")
+	End(span, nil)
+
+	var entry map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+}
+
+func TestEndRecordsError(t *testing.T) {
+	_, span := Start(context.Background(), "test.error")
+	End(span, errors.New("boom"))
+
+	s := ended(t, "test.error")
+	assert.Equal(t, codes.Error, s.Status().Code)
+	assert.Equal(t, "boom", s.Status().Description)
+}
+
+func TestTelegramCall(t *testing.T) {
+	ctx, parent := Start(context.Background(), "test.parent")
+
+	res, err := TelegramCall(ctx, "sendMessage", func() (int, error) { return 42, nil })
+	End(parent, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, res)
+
+	s := ended(t, "telegram.sendMessage")
+	assert.Equal(t, trace.SpanKindClient, s.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID())
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+
+	var handlerSpan trace.SpanContext
+	handler := func(ctx context.Context, req any) (any, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/telegram.v1.TelegramService/GetBotStatus"}
+	_, err := UnaryServerInterceptor()(ctx, nil, info, handler)
+	require.NoError(t, err)
+
+	s := ended(t, info.FullMethod)
+	assert.Equal(t, trace.SpanKindServer, s.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", s.Parent().SpanID().String())
+	assert.Equal(t, s.SpanContext().SpanID(), handlerSpan.SpanID())
+}
@@ -0,0 +1,987 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: telegram/v1/telegram_service.proto
+
+package telegrampb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TelegramServiceClient is the client API for TelegramService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TelegramServiceClient interface {
+	BotStatus(ctx context.Context, in *BotStatusRequest, opts ...grpc.CallOption) (*BotStatusResponse, error)
+	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error)
+	GetPrivateChat(ctx context.Context, in *GetPrivateChatRequest, opts ...grpc.CallOption) (*GetPrivateChatResponse, error)
+	StreamPrivateChats(ctx context.Context, in *StreamPrivateChatsRequest, opts ...grpc.CallOption) (TelegramService_StreamPrivateChatsClient, error)
+	ListSubscriptionEvents(ctx context.Context, in *ListSubscriptionEventsRequest, opts ...grpc.CallOption) (*ListSubscriptionEventsResponse, error)
+	GetSubscriptionChurn(ctx context.Context, in *GetSubscriptionChurnRequest, opts ...grpc.CallOption) (*GetSubscriptionChurnResponse, error)
+	SearchMessages(ctx context.Context, in *SearchMessagesRequest, opts ...grpc.CallOption) (*SearchMessagesResponse, error)
+	SendPhoto(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendPhotoClient, error)
+	SendDocument(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendDocumentClient, error)
+	SendSticker(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendStickerClient, error)
+	SendMediaGroup(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendMediaGroupClient, error)
+	EditMessageText(ctx context.Context, in *EditMessageTextRequest, opts ...grpc.CallOption) (*EditMessageTextResponse, error)
+	DeleteMessage(ctx context.Context, in *DeleteMessageRequest, opts ...grpc.CallOption) (*DeleteMessageResponse, error)
+	PinMessage(ctx context.Context, in *PinMessageRequest, opts ...grpc.CallOption) (*PinMessageResponse, error)
+	UnpinMessage(ctx context.Context, in *UnpinMessageRequest, opts ...grpc.CallOption) (*UnpinMessageResponse, error)
+	ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ForwardMessageResponse, error)
+	ReloadControllerConfig(ctx context.Context, in *ReloadControllerConfigRequest, opts ...grpc.CallOption) (*ReloadControllerConfigResponse, error)
+	BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error)
+	UnbanUser(ctx context.Context, in *UnbanUserRequest, opts ...grpc.CallOption) (*UnbanUserResponse, error)
+	ListBans(ctx context.Context, in *ListBansRequest, opts ...grpc.CallOption) (*ListBansResponse, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+}
+
+type telegramServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTelegramServiceClient(cc grpc.ClientConnInterface) TelegramServiceClient {
+	return &telegramServiceClient{cc}
+}
+
+func (c *telegramServiceClient) BotStatus(ctx context.Context, in *BotStatusRequest, opts ...grpc.CallOption) (*BotStatusResponse, error) {
+	out := new(BotStatusResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/BotStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error) {
+	out := new(SendMessageResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/SendMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) GetPrivateChat(ctx context.Context, in *GetPrivateChatRequest, opts ...grpc.CallOption) (*GetPrivateChatResponse, error) {
+	out := new(GetPrivateChatResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/GetPrivateChat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) StreamPrivateChats(ctx context.Context, in *StreamPrivateChatsRequest, opts ...grpc.CallOption) (TelegramService_StreamPrivateChatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TelegramService_ServiceDesc.Streams[0], "/telegram.v1.TelegramService/StreamPrivateChats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telegramServiceStreamPrivateChatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TelegramService_StreamPrivateChatsClient interface {
+	Recv() (*StreamPrivateChatsResponse, error)
+	grpc.ClientStream
+}
+
+type telegramServiceStreamPrivateChatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *telegramServiceStreamPrivateChatsClient) Recv() (*StreamPrivateChatsResponse, error) {
+	m := new(StreamPrivateChatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *telegramServiceClient) ListSubscriptionEvents(ctx context.Context, in *ListSubscriptionEventsRequest, opts ...grpc.CallOption) (*ListSubscriptionEventsResponse, error) {
+	out := new(ListSubscriptionEventsResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/ListSubscriptionEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) GetSubscriptionChurn(ctx context.Context, in *GetSubscriptionChurnRequest, opts ...grpc.CallOption) (*GetSubscriptionChurnResponse, error) {
+	out := new(GetSubscriptionChurnResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/GetSubscriptionChurn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) SearchMessages(ctx context.Context, in *SearchMessagesRequest, opts ...grpc.CallOption) (*SearchMessagesResponse, error) {
+	out := new(SearchMessagesResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/SearchMessages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) SendPhoto(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendPhotoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TelegramService_ServiceDesc.Streams[1], "/telegram.v1.TelegramService/SendPhoto", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telegramServiceSendPhotoClient{stream}
+	return x, nil
+}
+
+type TelegramService_SendPhotoClient interface {
+	Send(*SendPhotoRequest) error
+	CloseAndRecv() (*SendPhotoResponse, error)
+	grpc.ClientStream
+}
+
+type telegramServiceSendPhotoClient struct {
+	grpc.ClientStream
+}
+
+func (x *telegramServiceSendPhotoClient) Send(m *SendPhotoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendPhotoClient) CloseAndRecv() (*SendPhotoResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendPhotoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *telegramServiceClient) SendDocument(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendDocumentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TelegramService_ServiceDesc.Streams[2], "/telegram.v1.TelegramService/SendDocument", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telegramServiceSendDocumentClient{stream}
+	return x, nil
+}
+
+type TelegramService_SendDocumentClient interface {
+	Send(*SendDocumentRequest) error
+	CloseAndRecv() (*SendDocumentResponse, error)
+	grpc.ClientStream
+}
+
+type telegramServiceSendDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *telegramServiceSendDocumentClient) Send(m *SendDocumentRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendDocumentClient) CloseAndRecv() (*SendDocumentResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendDocumentResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *telegramServiceClient) SendSticker(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendStickerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TelegramService_ServiceDesc.Streams[3], "/telegram.v1.TelegramService/SendSticker", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telegramServiceSendStickerClient{stream}
+	return x, nil
+}
+
+type TelegramService_SendStickerClient interface {
+	Send(*SendStickerRequest) error
+	CloseAndRecv() (*SendStickerResponse, error)
+	grpc.ClientStream
+}
+
+type telegramServiceSendStickerClient struct {
+	grpc.ClientStream
+}
+
+func (x *telegramServiceSendStickerClient) Send(m *SendStickerRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendStickerClient) CloseAndRecv() (*SendStickerResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendStickerResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *telegramServiceClient) SendMediaGroup(ctx context.Context, opts ...grpc.CallOption) (TelegramService_SendMediaGroupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TelegramService_ServiceDesc.Streams[4], "/telegram.v1.TelegramService/SendMediaGroup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telegramServiceSendMediaGroupClient{stream}
+	return x, nil
+}
+
+type TelegramService_SendMediaGroupClient interface {
+	Send(*SendMediaGroupRequest) error
+	CloseAndRecv() (*SendMediaGroupResponse, error)
+	grpc.ClientStream
+}
+
+type telegramServiceSendMediaGroupClient struct {
+	grpc.ClientStream
+}
+
+func (x *telegramServiceSendMediaGroupClient) Send(m *SendMediaGroupRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendMediaGroupClient) CloseAndRecv() (*SendMediaGroupResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendMediaGroupResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *telegramServiceClient) EditMessageText(ctx context.Context, in *EditMessageTextRequest, opts ...grpc.CallOption) (*EditMessageTextResponse, error) {
+	out := new(EditMessageTextResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/EditMessageText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) DeleteMessage(ctx context.Context, in *DeleteMessageRequest, opts ...grpc.CallOption) (*DeleteMessageResponse, error) {
+	out := new(DeleteMessageResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/DeleteMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) PinMessage(ctx context.Context, in *PinMessageRequest, opts ...grpc.CallOption) (*PinMessageResponse, error) {
+	out := new(PinMessageResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/PinMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) UnpinMessage(ctx context.Context, in *UnpinMessageRequest, opts ...grpc.CallOption) (*UnpinMessageResponse, error) {
+	out := new(UnpinMessageResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/UnpinMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ForwardMessageResponse, error) {
+	out := new(ForwardMessageResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/ForwardMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) ReloadControllerConfig(ctx context.Context, in *ReloadControllerConfigRequest, opts ...grpc.CallOption) (*ReloadControllerConfigResponse, error) {
+	out := new(ReloadControllerConfigResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/ReloadControllerConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error) {
+	out := new(BanUserResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/BanUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) UnbanUser(ctx context.Context, in *UnbanUserRequest, opts ...grpc.CallOption) (*UnbanUserResponse, error) {
+	out := new(UnbanUserResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/UnbanUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) ListBans(ctx context.Context, in *ListBansRequest, opts ...grpc.CallOption) (*ListBansResponse, error) {
+	out := new(ListBansResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/ListBans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telegramServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/telegram.v1.TelegramService/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TelegramServiceServer is the server API for TelegramService service.
+// All implementations should embed UnimplementedTelegramServiceServer
+// for forward compatibility
+type TelegramServiceServer interface {
+	BotStatus(context.Context, *BotStatusRequest) (*BotStatusResponse, error)
+	SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error)
+	GetPrivateChat(context.Context, *GetPrivateChatRequest) (*GetPrivateChatResponse, error)
+	StreamPrivateChats(*StreamPrivateChatsRequest, TelegramService_StreamPrivateChatsServer) error
+	ListSubscriptionEvents(context.Context, *ListSubscriptionEventsRequest) (*ListSubscriptionEventsResponse, error)
+	GetSubscriptionChurn(context.Context, *GetSubscriptionChurnRequest) (*GetSubscriptionChurnResponse, error)
+	SearchMessages(context.Context, *SearchMessagesRequest) (*SearchMessagesResponse, error)
+	SendPhoto(TelegramService_SendPhotoServer) error
+	SendDocument(TelegramService_SendDocumentServer) error
+	SendSticker(TelegramService_SendStickerServer) error
+	SendMediaGroup(TelegramService_SendMediaGroupServer) error
+	EditMessageText(context.Context, *EditMessageTextRequest) (*EditMessageTextResponse, error)
+	DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error)
+	PinMessage(context.Context, *PinMessageRequest) (*PinMessageResponse, error)
+	UnpinMessage(context.Context, *UnpinMessageRequest) (*UnpinMessageResponse, error)
+	ForwardMessage(context.Context, *ForwardMessageRequest) (*ForwardMessageResponse, error)
+	ReloadControllerConfig(context.Context, *ReloadControllerConfigRequest) (*ReloadControllerConfigResponse, error)
+	BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error)
+	UnbanUser(context.Context, *UnbanUserRequest) (*UnbanUserResponse, error)
+	ListBans(context.Context, *ListBansRequest) (*ListBansResponse, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+}
+
+// UnimplementedTelegramServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedTelegramServiceServer struct {
+}
+
+func (UnimplementedTelegramServiceServer) BotStatus(context.Context, *BotStatusRequest) (*BotStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BotStatus not implemented")
+}
+func (UnimplementedTelegramServiceServer) SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+}
+func (UnimplementedTelegramServiceServer) GetPrivateChat(context.Context, *GetPrivateChatRequest) (*GetPrivateChatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrivateChat not implemented")
+}
+func (UnimplementedTelegramServiceServer) StreamPrivateChats(*StreamPrivateChatsRequest, TelegramService_StreamPrivateChatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPrivateChats not implemented")
+}
+func (UnimplementedTelegramServiceServer) ListSubscriptionEvents(context.Context, *ListSubscriptionEventsRequest) (*ListSubscriptionEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionEvents not implemented")
+}
+func (UnimplementedTelegramServiceServer) GetSubscriptionChurn(context.Context, *GetSubscriptionChurnRequest) (*GetSubscriptionChurnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionChurn not implemented")
+}
+func (UnimplementedTelegramServiceServer) SearchMessages(context.Context, *SearchMessagesRequest) (*SearchMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMessages not implemented")
+}
+func (UnimplementedTelegramServiceServer) SendPhoto(TelegramService_SendPhotoServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendPhoto not implemented")
+}
+func (UnimplementedTelegramServiceServer) SendDocument(TelegramService_SendDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendDocument not implemented")
+}
+func (UnimplementedTelegramServiceServer) SendSticker(TelegramService_SendStickerServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendSticker not implemented")
+}
+func (UnimplementedTelegramServiceServer) SendMediaGroup(TelegramService_SendMediaGroupServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendMediaGroup not implemented")
+}
+func (UnimplementedTelegramServiceServer) EditMessageText(context.Context, *EditMessageTextRequest) (*EditMessageTextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditMessageText not implemented")
+}
+func (UnimplementedTelegramServiceServer) DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMessage not implemented")
+}
+func (UnimplementedTelegramServiceServer) PinMessage(context.Context, *PinMessageRequest) (*PinMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinMessage not implemented")
+}
+func (UnimplementedTelegramServiceServer) UnpinMessage(context.Context, *UnpinMessageRequest) (*UnpinMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpinMessage not implemented")
+}
+func (UnimplementedTelegramServiceServer) ForwardMessage(context.Context, *ForwardMessageRequest) (*ForwardMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardMessage not implemented")
+}
+func (UnimplementedTelegramServiceServer) ReloadControllerConfig(context.Context, *ReloadControllerConfigRequest) (*ReloadControllerConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadControllerConfig not implemented")
+}
+func (UnimplementedTelegramServiceServer) BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanUser not implemented")
+}
+func (UnimplementedTelegramServiceServer) UnbanUser(context.Context, *UnbanUserRequest) (*UnbanUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnbanUser not implemented")
+}
+func (UnimplementedTelegramServiceServer) ListBans(context.Context, *ListBansRequest) (*ListBansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBans not implemented")
+}
+func (UnimplementedTelegramServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+
+// UnsafeTelegramServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TelegramServiceServer will
+// result in compilation errors.
+type UnsafeTelegramServiceServer interface {
+	mustEmbedUnimplementedTelegramServiceServer()
+}
+
+func RegisterTelegramServiceServer(s grpc.ServiceRegistrar, srv TelegramServiceServer) {
+	s.RegisterService(&TelegramService_ServiceDesc, srv)
+}
+
+func _TelegramService_BotStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BotStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).BotStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/BotStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).BotStatus(ctx, req.(*BotStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/SendMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).SendMessage(ctx, req.(*SendMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_GetPrivateChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrivateChatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).GetPrivateChat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/GetPrivateChat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).GetPrivateChat(ctx, req.(*GetPrivateChatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_StreamPrivateChats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPrivateChatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TelegramServiceServer).StreamPrivateChats(m, &telegramServiceStreamPrivateChatsServer{stream})
+}
+
+type TelegramService_StreamPrivateChatsServer interface {
+	Send(*StreamPrivateChatsResponse) error
+	grpc.ServerStream
+}
+
+type telegramServiceStreamPrivateChatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *telegramServiceStreamPrivateChatsServer) Send(m *StreamPrivateChatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TelegramService_ListSubscriptionEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).ListSubscriptionEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/ListSubscriptionEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).ListSubscriptionEvents(ctx, req.(*ListSubscriptionEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_GetSubscriptionChurn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionChurnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).GetSubscriptionChurn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/GetSubscriptionChurn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).GetSubscriptionChurn(ctx, req.(*GetSubscriptionChurnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_SearchMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).SearchMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/SearchMessages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).SearchMessages(ctx, req.(*SearchMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_SendPhoto_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TelegramServiceServer).SendPhoto(&telegramServiceSendPhotoServer{stream})
+}
+
+type TelegramService_SendPhotoServer interface {
+	SendAndClose(*SendPhotoResponse) error
+	Recv() (*SendPhotoRequest, error)
+	grpc.ServerStream
+}
+
+type telegramServiceSendPhotoServer struct {
+	grpc.ServerStream
+}
+
+func (x *telegramServiceSendPhotoServer) SendAndClose(m *SendPhotoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendPhotoServer) Recv() (*SendPhotoRequest, error) {
+	m := new(SendPhotoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TelegramService_SendDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TelegramServiceServer).SendDocument(&telegramServiceSendDocumentServer{stream})
+}
+
+type TelegramService_SendDocumentServer interface {
+	SendAndClose(*SendDocumentResponse) error
+	Recv() (*SendDocumentRequest, error)
+	grpc.ServerStream
+}
+
+type telegramServiceSendDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *telegramServiceSendDocumentServer) SendAndClose(m *SendDocumentResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendDocumentServer) Recv() (*SendDocumentRequest, error) {
+	m := new(SendDocumentRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TelegramService_SendSticker_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TelegramServiceServer).SendSticker(&telegramServiceSendStickerServer{stream})
+}
+
+type TelegramService_SendStickerServer interface {
+	SendAndClose(*SendStickerResponse) error
+	Recv() (*SendStickerRequest, error)
+	grpc.ServerStream
+}
+
+type telegramServiceSendStickerServer struct {
+	grpc.ServerStream
+}
+
+func (x *telegramServiceSendStickerServer) SendAndClose(m *SendStickerResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendStickerServer) Recv() (*SendStickerRequest, error) {
+	m := new(SendStickerRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TelegramService_SendMediaGroup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TelegramServiceServer).SendMediaGroup(&telegramServiceSendMediaGroupServer{stream})
+}
+
+type TelegramService_SendMediaGroupServer interface {
+	SendAndClose(*SendMediaGroupResponse) error
+	Recv() (*SendMediaGroupRequest, error)
+	grpc.ServerStream
+}
+
+type telegramServiceSendMediaGroupServer struct {
+	grpc.ServerStream
+}
+
+func (x *telegramServiceSendMediaGroupServer) SendAndClose(m *SendMediaGroupResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *telegramServiceSendMediaGroupServer) Recv() (*SendMediaGroupRequest, error) {
+	m := new(SendMediaGroupRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TelegramService_EditMessageText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditMessageTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).EditMessageText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/EditMessageText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).EditMessageText(ctx, req.(*EditMessageTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_DeleteMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).DeleteMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/DeleteMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).DeleteMessage(ctx, req.(*DeleteMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_PinMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).PinMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/PinMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).PinMessage(ctx, req.(*PinMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_UnpinMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpinMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).UnpinMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/UnpinMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).UnpinMessage(ctx, req.(*UnpinMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_ForwardMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).ForwardMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/ForwardMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).ForwardMessage(ctx, req.(*ForwardMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_ReloadControllerConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadControllerConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).ReloadControllerConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/ReloadControllerConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).ReloadControllerConfig(ctx, req.(*ReloadControllerConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_BanUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).BanUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/BanUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).BanUser(ctx, req.(*BanUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_UnbanUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbanUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).UnbanUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/UnbanUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).UnbanUser(ctx, req.(*UnbanUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_ListBans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).ListBans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/ListBans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).ListBans(ctx, req.(*ListBansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelegramService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelegramServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telegram.v1.TelegramService/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelegramServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TelegramService_ServiceDesc is the grpc.ServiceDesc for TelegramService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TelegramService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "telegram.v1.TelegramService",
+	HandlerType: (*TelegramServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BotStatus",
+			Handler:    _TelegramService_BotStatus_Handler,
+		},
+		{
+			MethodName: "SendMessage",
+			Handler:    _TelegramService_SendMessage_Handler,
+		},
+		{
+			MethodName: "GetPrivateChat",
+			Handler:    _TelegramService_GetPrivateChat_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionEvents",
+			Handler:    _TelegramService_ListSubscriptionEvents_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionChurn",
+			Handler:    _TelegramService_GetSubscriptionChurn_Handler,
+		},
+		{
+			MethodName: "SearchMessages",
+			Handler:    _TelegramService_SearchMessages_Handler,
+		},
+		{
+			MethodName: "EditMessageText",
+			Handler:    _TelegramService_EditMessageText_Handler,
+		},
+		{
+			MethodName: "DeleteMessage",
+			Handler:    _TelegramService_DeleteMessage_Handler,
+		},
+		{
+			MethodName: "PinMessage",
+			Handler:    _TelegramService_PinMessage_Handler,
+		},
+		{
+			MethodName: "UnpinMessage",
+			Handler:    _TelegramService_UnpinMessage_Handler,
+		},
+		{
+			MethodName: "ForwardMessage",
+			Handler:    _TelegramService_ForwardMessage_Handler,
+		},
+		{
+			MethodName: "ReloadControllerConfig",
+			Handler:    _TelegramService_ReloadControllerConfig_Handler,
+		},
+		{
+			MethodName: "BanUser",
+			Handler:    _TelegramService_BanUser_Handler,
+		},
+		{
+			MethodName: "UnbanUser",
+			Handler:    _TelegramService_UnbanUser_Handler,
+		},
+		{
+			MethodName: "ListBans",
+			Handler:    _TelegramService_ListBans_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _TelegramService_ListAuditEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPrivateChats",
+			Handler:       _TelegramService_StreamPrivateChats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SendPhoto",
+			Handler:       _TelegramService_SendPhoto_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SendDocument",
+			Handler:       _TelegramService_SendDocument_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SendSticker",
+			Handler:       _TelegramService_SendSticker_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SendMediaGroup",
+			Handler:       _TelegramService_SendMediaGroup_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "telegram/v1/telegram_service.proto",
+}